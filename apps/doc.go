@@ -0,0 +1,3 @@
+// Package apps provides functionality for enumerating the Steam apps
+// that are installed in Steam's libraries.
+package apps
@@ -0,0 +1,319 @@
+package apps
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+const (
+	manifestFilePrefix    = "appmanifest_"
+	manifestFileExtension = ".acf"
+	commonDirName         = "common"
+)
+
+// Manifest describes an app that is installed in a Steam library, as
+// described by its app manifest (.acf) file.
+type Manifest struct {
+	// AppId is the app's Steam app ID.
+	AppId string
+
+	// Name is the app's name.
+	Name string
+
+	// InstallDir is the name of the app's install directory, as
+	// stored in the manifest.
+	InstallDir string
+
+	// InstallDirPath is the resolved path to the app's install
+	// directory. It is only set when the manifest is read from
+	// a library.
+	InstallDirPath string
+
+	// StateFlags describes the app's install state.
+	StateFlags StateFlags
+
+	// SizeOnDisk is the size of the app's files in bytes.
+	SizeOnDisk int64
+
+	// BuildId is the ID of the app's installed build.
+	BuildId string
+
+	// LastUpdatedEpoch is the time that the app was last updated,
+	// in seconds since the Unix epoch.
+	LastUpdatedEpoch int64
+
+	// UserConfig contains the user's configuration for the app,
+	// such as its language and beta branch.
+	UserConfig map[string]string
+
+	// MountedConfig contains the configuration of the app's
+	// currently installed files.
+	MountedConfig map[string]string
+
+	// LibraryPath is the path to the library that contains the app.
+	// It is only set when the manifest is read from a library.
+	LibraryPath string
+
+	// ManifestFilePath is the path to the manifest file. It is only
+	// set when the manifest is read from a file.
+	ManifestFilePath string
+}
+
+// ParseManifest parses an app manifest (.acf) file.
+func ParseManifest(r io.Reader) (Manifest, error) {
+	doc, err := vdf.ParseText(r)
+	if err != nil {
+		return Manifest{}, errors.New("failed to parse app manifest - " + err.Error())
+	}
+
+	state, ok := doc.Get("AppState")
+	if !ok || !state.IsObject {
+		return Manifest{}, errors.New("the app manifest is missing its 'AppState' object")
+	}
+
+	manifest := Manifest{}
+
+	manifest.AppId, _ = state.StringValue("appid")
+	if len(manifest.AppId) == 0 {
+		return manifest, errors.New("the app manifest is missing its app ID")
+	}
+
+	manifest.Name, _ = state.StringValue("name")
+	manifest.InstallDir, _ = state.StringValue("installdir")
+	manifest.BuildId, _ = state.StringValue("buildid")
+
+	stateFlags, err := parseManifestInt(state, "StateFlags")
+	if err != nil {
+		return manifest, err
+	}
+	manifest.StateFlags = StateFlags(stateFlags)
+
+	manifest.SizeOnDisk, err = parseManifestInt(state, "SizeOnDisk")
+	if err != nil {
+		return manifest, err
+	}
+
+	manifest.LastUpdatedEpoch, err = parseManifestInt(state, "LastUpdated")
+	if err != nil {
+		return manifest, err
+	}
+
+	manifest.UserConfig = manifestStringMap(state, "UserConfig")
+	manifest.MountedConfig = manifestStringMap(state, "MountedConfig")
+
+	return manifest, nil
+}
+
+func parseManifestInt(state *vdf.KeyValue, key string) (int64, error) {
+	raw, ok := state.StringValue(key)
+	if !ok || len(raw) == 0 {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, errors.New("failed to parse app manifest field '" + key + "' - " + err.Error())
+	}
+
+	return value, nil
+}
+
+func manifestStringMap(state *vdf.KeyValue, key string) map[string]string {
+	object, ok := state.Get(key)
+	if !ok || !object.IsObject {
+		return nil
+	}
+
+	result := make(map[string]string)
+
+	for _, child := range object.Children {
+		if !child.IsObject {
+			result[child.Key] = child.Value
+		}
+	}
+
+	return result
+}
+
+// ReadManifest reads the specified app manifest file. The manifest's
+// library and install directory paths are resolved relative to the
+// steamapps directory that contains the file.
+func ReadManifest(filePath string) (Manifest, error) {
+	return readManifest(locations.OSFileSystem(), filePath)
+}
+
+func readManifest(fs locations.FileSystem, filePath string) (Manifest, error) {
+	f, err := fs.Open(filePath)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer f.Close()
+
+	manifest, err := ParseManifest(f)
+	if err != nil {
+		return manifest, errors.New("failed to read '" + filePath + "' - " + err.Error())
+	}
+
+	manifest.ManifestFilePath = filePath
+	manifest.LibraryPath = path.Dir(path.Dir(filePath))
+	manifest.InstallDirPath = InstallDirPath(manifest.LibraryPath, manifest.InstallDir)
+
+	return manifest, nil
+}
+
+// ManifestError is returned when one or more app manifests could not
+// be read.
+type ManifestError struct {
+	// Failures maps the paths of the manifests that could not be read
+	// to the error that occurred.
+	Failures map[string]error
+}
+
+func (o *ManifestError) Error() string {
+	var paths []string
+
+	for p := range o.Failures {
+		paths = append(paths, p)
+	}
+
+	sort.Strings(paths)
+
+	var messages []string
+
+	for _, p := range paths {
+		messages = append(messages, o.Failures[p].Error())
+	}
+
+	return "failed to read " + strconv.Itoa(len(paths)) + " app manifest(s): " +
+		strings.Join(messages, ", ")
+}
+
+// LibraryManifests returns the app manifests in the specified library,
+// which is one of the libraries returned by locations.Libraries for the
+// DataVerifier.
+//
+// Every manifest is attempted. If any could not be read, a *ManifestError
+// is returned along with the manifests that were read.
+func LibraryManifests(dv locations.DataVerifier, library locations.Library) ([]Manifest, error) {
+	fs := locations.FileSystemOf(dv)
+	steamAppsDirPath := library.SteamAppsDirPath()
+
+	infos, err := fs.ReadDir(steamAppsDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests []Manifest
+	failures := make(map[string]error)
+
+	for _, info := range infos {
+		if info.IsDir() || !isManifestFileName(info.Name()) {
+			continue
+		}
+
+		manifestFilePath := path.Join(steamAppsDirPath, info.Name())
+
+		manifest, err := readManifest(fs, manifestFilePath)
+		if err != nil {
+			failures[manifestFilePath] = err
+			continue
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	if len(failures) > 0 {
+		return manifests, &ManifestError{
+			Failures: failures,
+		}
+	}
+
+	return manifests, nil
+}
+
+// Installed returns the app manifests in every library of the DataVerifier's
+// Steam data directory. Libraries that are not currently available,
+// such as those on a disconnected drive, are skipped. An app that is
+// listed more than once in the same library is only returned once.
+//
+// Every library is attempted. If any manifests could not be read,
+// a *ManifestError is returned along with the manifests that were read.
+func Installed(dv locations.DataVerifier) ([]Manifest, error) {
+	libraries, err := locations.Libraries(dv)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests []Manifest
+	seen := make(map[string]bool)
+	failures := make(map[string]error)
+
+	for _, library := range libraries {
+		libraryManifests, err := LibraryManifests(dv, library)
+		if err != nil {
+			switch e := err.(type) {
+			case *ManifestError:
+				for p, failure := range e.Failures {
+					failures[p] = failure
+				}
+			default:
+				if os.IsNotExist(err) {
+					continue
+				}
+
+				return manifests, err
+			}
+		}
+
+		for _, manifest := range libraryManifests {
+			key := ManifestFilePath(library.Path, manifest.AppId)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			manifests = append(manifests, manifest)
+		}
+	}
+
+	if len(failures) > 0 {
+		return manifests, &ManifestError{
+			Failures: failures,
+		}
+	}
+
+	return manifests, nil
+}
+
+// InstallDirPath generates a path to an app's install directory for the
+// specified library directory and manifest install directory.
+func InstallDirPath(libraryDirPath string, installDir string) string {
+	if len(installDir) == 0 {
+		return ""
+	}
+
+	if filepath.IsAbs(installDir) || path.IsAbs(installDir) {
+		return installDir
+	}
+
+	return path.Join(locations.SteamAppsDirPath(libraryDirPath), commonDirName, installDir)
+}
+
+// ManifestFilePath generates a path to the manifest file of the specified
+// app ID in the specified library directory.
+func ManifestFilePath(libraryDirPath string, appId string) string {
+	return path.Join(locations.SteamAppsDirPath(libraryDirPath), manifestFilePrefix+appId+manifestFileExtension)
+}
+
+func isManifestFileName(name string) bool {
+	return strings.HasPrefix(name, manifestFilePrefix) && strings.HasSuffix(name, manifestFileExtension)
+}
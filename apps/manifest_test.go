@@ -0,0 +1,181 @@
+package apps
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stephen-fox/steamutil/locations"
+)
+
+const (
+	testDataSubDir       = "/.testdata/"
+	appManifestAcfSubDir = testDataSubDir + "appmanifest-acf/"
+)
+
+func TestInstalled(t *testing.T) {
+	dataDirPath, err := ioutil.TempDir("", "steamutil-apps-test")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dataDirPath)
+
+	secondLibraryPath := path.Join(dataDirPath, "second")
+	linkedLibraryPath := path.Join(dataDirPath, "linked")
+
+	for _, p := range []string{locations.SteamAppsDirPath(dataDirPath), locations.SteamAppsDirPath(secondLibraryPath)} {
+		err := os.MkdirAll(p, 0700)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	libraryFolders := "\"libraryfolders\"\n{\n" +
+		"\t\"0\"\n\t{\n\t\t\"path\"\t\t\"" + dataDirPath + "\"\n\t}\n" +
+		"\t\"1\"\n\t{\n\t\t\"path\"\t\t\"" + secondLibraryPath + "\"\n\t}\n" +
+		"\t\"2\"\n\t{\n\t\t\"path\"\t\t\"" + path.Join(dataDirPath, "missing") + "\"\n\t}\n" +
+		"\t\"3\"\n\t{\n\t\t\"path\"\t\t\"" + linkedLibraryPath + "\"\n\t}\n}\n"
+
+	err = ioutil.WriteFile(locations.LibraryFoldersFilePath(dataDirPath), []byte(libraryFolders), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	copyTestManifest(t, "appmanifest_400.acf", dataDirPath)
+	copyTestManifest(t, "appmanifest_220.acf", secondLibraryPath)
+
+	err = ioutil.WriteFile(path.Join(locations.SteamAppsDirPath(dataDirPath), "appmanifest_1.acf.tmp"), nil, 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	badManifestPath := path.Join(locations.SteamAppsDirPath(dataDirPath), "appmanifest_2.acf")
+
+	err = ioutil.WriteFile(badManifestPath, []byte("\"AppState\"\n{\n"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = os.Symlink(secondLibraryPath, linkedLibraryPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	dv, err := locations.NewDataVerifierForDir(dataDirPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	manifests, err := Installed(dv)
+
+	manifestErr, ok := err.(*ManifestError)
+	if !ok {
+		t.Fatal("Expected a *ManifestError - got", err)
+	}
+
+	if len(manifestErr.Failures) != 1 || manifestErr.Failures[badManifestPath] == nil {
+		t.Fatal("Unexpected manifest failures -", manifestErr.Failures)
+	}
+
+	if len(manifests) != 2 {
+		t.Fatal("Unexpected number of manifests -", manifests)
+	}
+
+	portal := manifests[0]
+
+	if portal.AppId != "400" || portal.Name != "Portal" || portal.SizeOnDisk != 4418394354 ||
+		portal.BuildId != "5757163" || portal.LastUpdatedEpoch != 1700000000 ||
+		portal.MountedConfig["language"] != "english" {
+		t.Fatal("Unexpected manifest -", portal)
+	}
+
+	if !portal.StateFlags.IsInstalled() {
+		t.Fatal("Expected app to be installed - " + portal.StateFlags.String())
+	}
+
+	if portal.InstallDirPath != path.Join(dataDirPath, "steamapps", "common", "Portal") {
+		t.Fatal("Unexpected install directory path - '" + portal.InstallDirPath + "'")
+	}
+
+	hl2 := manifests[1]
+
+	if hl2.LibraryPath != secondLibraryPath || hl2.UserConfig["betakey"] != "beta" {
+		t.Fatal("Unexpected manifest -", hl2)
+	}
+
+	if hl2.StateFlags.String() != "update required, update started" {
+		t.Fatal("Unexpected state - " + hl2.StateFlags.String())
+	}
+}
+
+func TestInstalledFSDataVerifier(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	portal, err := ioutil.ReadFile(path.Dir(wd) + appManifestAcfSubDir + "appmanifest_400.acf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	hl2, err := ioutil.ReadFile(path.Dir(wd) + appManifestAcfSubDir + "appmanifest_220.acf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	libraryFolders := "\"libraryfolders\"\n{\n" +
+		"\t\"0\"\n\t{\n\t\t\"path\"\t\t\"/home/deck/.local/share/Steam\"\n\t}\n" +
+		"\t\"1\"\n\t{\n\t\t\"path\"\t\t\"/run/media/mmcblk0p1/SteamLibrary\"\n\t}\n" +
+		"\t\"2\"\n\t{\n\t\t\"path\"\t\t\"/home/deck/.local/share/Steam/extra\"\n\t}\n}\n"
+
+	dv, err := locations.NewFSDataVerifier(fstest.MapFS{
+		"steamapps/libraryfolders.vdf":        &fstest.MapFile{Data: []byte(libraryFolders)},
+		"steamapps/appmanifest_400.acf":       &fstest.MapFile{Data: portal},
+		"extra/steamapps/appmanifest_220.acf": &fstest.MapFile{Data: hl2},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	manifests, err := Installed(dv)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(manifests) != 2 || manifests[0].AppId != "400" || manifests[0].LibraryPath != "." ||
+		manifests[1].AppId != "220" || manifests[1].LibraryPath != "extra" {
+		t.Fatal("Unexpected manifests -", manifests)
+	}
+}
+
+func TestStateFlags_String(t *testing.T) {
+	flags := StateFullyInstalled | StateFlags(1<<30)
+
+	if flags.String() != "installed, unknown (1073741824)" {
+		t.Fatal("Unexpected state - " + flags.String())
+	}
+
+	if StateFlags(0).String() != "invalid" {
+		t.Fatal("Unexpected state for zero flags - " + StateFlags(0).String())
+	}
+}
+
+func copyTestManifest(t *testing.T, name string, libraryDirPath string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	raw, err := ioutil.ReadFile(path.Dir(wd) + appManifestAcfSubDir + name)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = ioutil.WriteFile(path.Join(locations.SteamAppsDirPath(libraryDirPath), name), raw, 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
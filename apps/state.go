@@ -0,0 +1,154 @@
+package apps
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StateFlags is a bit set describing the state of an app, as stored in
+// the 'StateFlags' field of an app manifest.
+type StateFlags uint32
+
+const (
+	// StateUninstalled means that the app is not installed.
+	StateUninstalled StateFlags = 1 << 0
+
+	// StateUpdateRequired means that the app must be updated before
+	// it can be played.
+	StateUpdateRequired StateFlags = 1 << 1
+
+	// StateFullyInstalled means that all of the app's files are
+	// installed and up to date.
+	StateFullyInstalled StateFlags = 1 << 2
+
+	// StateEncrypted means that the app's files are encrypted, such as
+	// when they were preloaded before the app's release.
+	StateEncrypted StateFlags = 1 << 3
+
+	// StateLocked means that the app is locked by another Steam
+	// operation.
+	StateLocked StateFlags = 1 << 4
+
+	// StateFilesMissing means that some of the app's files are missing.
+	StateFilesMissing StateFlags = 1 << 5
+
+	// StateAppRunning means that the app is running.
+	StateAppRunning StateFlags = 1 << 6
+
+	// StateFilesCorrupt means that some of the app's files are corrupt.
+	StateFilesCorrupt StateFlags = 1 << 7
+
+	// StateUpdateRunning means that the app is being updated.
+	StateUpdateRunning StateFlags = 1 << 8
+
+	// StateUpdatePaused means that the app's update is paused.
+	StateUpdatePaused StateFlags = 1 << 9
+
+	// StateUpdateStarted means that the app's update has been started
+	// or queued.
+	StateUpdateStarted StateFlags = 1 << 10
+
+	// StateUninstalling means that the app is being uninstalled.
+	StateUninstalling StateFlags = 1 << 11
+
+	// StateBackupRunning means that the app is being backed up.
+	StateBackupRunning StateFlags = 1 << 12
+
+	// StateReconfiguring means that the app is being reconfigured, such
+	// as after its language or DLC changed.
+	StateReconfiguring StateFlags = 1 << 16
+
+	// StateValidating means that the app's files are being verified.
+	StateValidating StateFlags = 1 << 17
+
+	// StateAddingFiles means that files are being added to the app's
+	// install.
+	StateAddingFiles StateFlags = 1 << 18
+
+	// StatePreallocating means that disk space is being allocated for
+	// the app's files.
+	StatePreallocating StateFlags = 1 << 19
+
+	// StateDownloading means that the app's files are being downloaded.
+	StateDownloading StateFlags = 1 << 20
+
+	// StateStaging means that downloaded files are being staged before
+	// they are committed.
+	StateStaging StateFlags = 1 << 21
+
+	// StateCommitting means that staged files are being moved into the
+	// app's install.
+	StateCommitting StateFlags = 1 << 22
+
+	// StateUpdateStopping means that the app's update is being stopped.
+	StateUpdateStopping StateFlags = 1 << 23
+)
+
+var stateNames = []struct {
+	flag StateFlags
+	name string
+}{
+	{StateUninstalled, "uninstalled"},
+	{StateUpdateRequired, "update required"},
+	{StateFullyInstalled, "installed"},
+	{StateEncrypted, "encrypted"},
+	{StateLocked, "locked"},
+	{StateFilesMissing, "files missing"},
+	{StateAppRunning, "running"},
+	{StateFilesCorrupt, "files corrupt"},
+	{StateUpdateRunning, "updating"},
+	{StateUpdatePaused, "update paused"},
+	{StateUpdateStarted, "update started"},
+	{StateUninstalling, "uninstalling"},
+	{StateBackupRunning, "backing up"},
+	{StateReconfiguring, "reconfiguring"},
+	{StateValidating, "validating"},
+	{StateAddingFiles, "adding files"},
+	{StatePreallocating, "preallocating"},
+	{StateDownloading, "downloading"},
+	{StateStaging, "staging"},
+	{StateCommitting, "committing"},
+	{StateUpdateStopping, "update stopping"},
+}
+
+// Has returns true if all of the specified flags are set.
+func (o StateFlags) Has(flags StateFlags) bool {
+	return o&flags == flags
+}
+
+// IsInstalled returns true if the app is fully installed.
+func (o StateFlags) IsInstalled() bool {
+	return o.Has(StateFullyInstalled)
+}
+
+// Names returns the names of the flags that are set. Unknown flags
+// are named after their value.
+func (o StateFlags) Names() []string {
+	var names []string
+	remaining := o
+
+	for _, state := range stateNames {
+		if o.Has(state.flag) {
+			names = append(names, state.name)
+			remaining &^= state.flag
+		}
+	}
+
+	for bit := uint(0); remaining != 0; bit++ {
+		flag := StateFlags(1) << bit
+		if remaining&flag != 0 {
+			names = append(names, "unknown ("+strconv.FormatUint(uint64(flag), 10)+")")
+			remaining &^= flag
+		}
+	}
+
+	return names
+}
+
+func (o StateFlags) String() string {
+	if o == 0 {
+		return "invalid"
+	}
+
+	return strings.Join(o.Names(), ", ")
+}
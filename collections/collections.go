@@ -0,0 +1,662 @@
+package collections
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/naming"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+const (
+	// FavoritesId is the ID of the collection containing the user's
+	// favorite apps.
+	FavoritesId = "favorite"
+
+	// HiddenId is the ID of the collection containing the apps that
+	// the user has hidden.
+	HiddenId = "hidden"
+
+	keyPrefix       = "user-collections."
+	newIdPrefix     = "uc-"
+	newIdLength     = 12
+	newIdCharacters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+	entryKeyKey             = "key"
+	entryTimestampKey       = "timestamp"
+	entryValueKey           = "value"
+	entryVersionKey         = "version"
+	entryIsDeletedKey       = "is_deleted"
+	entryConflictMethodKey  = "conflictResolutionMethod"
+	entryStrMethodIdKey     = "strMethodId"
+	defaultConflictMethod   = "custom"
+	defaultStrMethodId      = "union-collections"
+	collectionIdKey         = "id"
+	collectionNameKey       = "name"
+	collectionAddedKey      = "added"
+	collectionRemovedKey    = "removed"
+	collectionFilterSpecKey = "filterSpec"
+)
+
+// Collection is a user-defined group of Steam apps and non-Steam shortcuts.
+type Collection struct {
+	// Id is the collection's ID, such as 'uc-0123456789ab', FavoritesId
+	// or HiddenId.
+	Id string
+
+	// Name is the name of the collection.
+	Name string
+
+	// AppIds are the IDs of the apps and shortcuts that were added to
+	// the collection.
+	AppIds []string
+
+	// RemovedAppIds are the IDs of the apps and shortcuts that were
+	// removed from a dynamic collection even though they match its
+	// filters.
+	RemovedAppIds []string
+
+	// IsDynamic is true if the collection's contents are determined by
+	// filters. The filters are preserved, but are not exposed.
+	IsDynamic bool
+}
+
+// IsBuiltIn returns true if the collection was created by Steam rather
+// than by the user. Built-in collections cannot be renamed or deleted.
+func (o Collection) IsBuiltIn() bool {
+	return o.Id == FavoritesId || o.Id == HiddenId
+}
+
+// entry is an entry in the cloud storage file. Fields that are not
+// modified by this package are preserved as-is.
+type entry struct {
+	key    string
+	fields map[string]json.RawMessage
+}
+
+// CloudStorage is a parsed cloud storage file. Everything in the file is
+// preserved when it is written, including entries that are not
+// collections.
+//
+// Steam uses the version of each entry to reconcile the file with its copy
+// in Steam Cloud, so the versions of existing entries are preserved when
+// they are modified. Only their timestamps are updated.
+type CloudStorage struct {
+	entries []*entry
+}
+
+// Collections returns the collections in the file, sorted by name.
+// Deleted collections are excluded.
+func (o *CloudStorage) Collections() []Collection {
+	var result []Collection
+
+	for _, e := range o.entries {
+		collection, ok := e.collection()
+		if ok {
+			result = append(result, collection)
+		}
+	}
+
+	sort.SliceStable(result, func(i int, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+
+	return result
+}
+
+// Collection returns the collection with the specified ID.
+func (o *CloudStorage) Collection(id string) (Collection, bool) {
+	e, ok := o.entry(id)
+	if !ok {
+		return Collection{}, false
+	}
+
+	return e.collection()
+}
+
+// CreateCollection creates an empty collection with the specified name.
+func (o *CloudStorage) CreateCollection(name string) (Collection, error) {
+	err := o.checkName(name, "")
+	if err != nil {
+		return Collection{}, err
+	}
+
+	id, err := newCollectionId()
+	if err != nil {
+		return Collection{}, err
+	}
+
+	_, exists := o.entry(id)
+	if exists {
+		return Collection{}, errors.New("the generated collection ID '" + id + "' is already in use")
+	}
+
+	collection := Collection{
+		Id:   id,
+		Name: name,
+	}
+
+	e := &entry{
+		key:    keyPrefix + id,
+		fields: make(map[string]json.RawMessage),
+	}
+
+	e.setString(entryKeyKey, e.key)
+	e.setString(entryVersionKey, o.nextVersion())
+	e.setString(entryConflictMethodKey, defaultConflictMethod)
+	e.setString(entryStrMethodIdKey, defaultStrMethodId)
+
+	err = e.setCollection(collection, map[string]json.RawMessage{})
+	if err != nil {
+		return Collection{}, err
+	}
+
+	o.entries = append(o.entries, e)
+
+	return collection, nil
+}
+
+// RenameCollection renames the collection with the specified ID.
+func (o *CloudStorage) RenameCollection(id string, name string) error {
+	e, collection, value, err := o.modifiable(id)
+	if err != nil {
+		return err
+	}
+
+	if collection.IsBuiltIn() {
+		return errors.New("the built-in collection '" + id + "' cannot be renamed")
+	}
+
+	err = o.checkName(name, id)
+	if err != nil {
+		return err
+	}
+
+	collection.Name = name
+
+	return e.setCollection(collection, value)
+}
+
+// DeleteCollection deletes the collection with the specified ID.
+func (o *CloudStorage) DeleteCollection(id string) error {
+	e, collection, _, err := o.modifiable(id)
+	if err != nil {
+		return err
+	}
+
+	if collection.IsBuiltIn() {
+		return errors.New("the built-in collection '" + id + "' cannot be deleted")
+	}
+
+	delete(e.fields, entryValueKey)
+	e.fields[entryIsDeletedKey] = json.RawMessage("true")
+	e.touch()
+
+	return nil
+}
+
+// AddApps adds the specified Steam app IDs or non-Steam shortcut app IDs
+// to the collection with the specified ID.
+func (o *CloudStorage) AddApps(id string, appIds ...string) error {
+	e, collection, value, err := o.modifiable(id)
+	if err != nil {
+		return err
+	}
+
+	for _, appId := range appIds {
+		err := checkAppId(appId)
+		if err != nil {
+			return err
+		}
+
+		collection.RemovedAppIds = removeString(collection.RemovedAppIds, appId)
+
+		if !containsString(collection.AppIds, appId) {
+			collection.AppIds = append(collection.AppIds, appId)
+		}
+	}
+
+	return e.setCollection(collection, value)
+}
+
+// RemoveApps removes the specified Steam app IDs or non-Steam shortcut
+// app IDs from the collection with the specified ID. Apps removed from
+// a dynamic collection are recorded so that its filters do not add
+// them again.
+func (o *CloudStorage) RemoveApps(id string, appIds ...string) error {
+	e, collection, value, err := o.modifiable(id)
+	if err != nil {
+		return err
+	}
+
+	for _, appId := range appIds {
+		err := checkAppId(appId)
+		if err != nil {
+			return err
+		}
+
+		collection.AppIds = removeString(collection.AppIds, appId)
+
+		if collection.IsDynamic && !containsString(collection.RemovedAppIds, appId) {
+			collection.RemovedAppIds = append(collection.RemovedAppIds, appId)
+		}
+	}
+
+	return e.setCollection(collection, value)
+}
+
+// AddShortcuts adds the specified non-Steam shortcuts to the collection
+// with the specified ID.
+func (o *CloudStorage) AddShortcuts(id string, scs ...shortcuts.Shortcut) error {
+	return o.AddApps(id, shortcutAppIds(scs)...)
+}
+
+// RemoveShortcuts removes the specified non-Steam shortcuts from the
+// collection with the specified ID.
+func (o *CloudStorage) RemoveShortcuts(id string, scs ...shortcuts.Shortcut) error {
+	return o.RemoveApps(id, shortcutAppIds(scs)...)
+}
+
+// Write writes the cloud storage file to the specified io.Writer.
+func (o *CloudStorage) Write(w io.Writer) error {
+	pairs := make([][2]interface{}, 0, len(o.entries))
+
+	for _, e := range o.entries {
+		pairs = append(pairs, [2]interface{}{e.key, e.fields})
+	}
+
+	raw, err := json.Marshal(pairs)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(raw)
+
+	return err
+}
+
+func (o *CloudStorage) entry(id string) (*entry, bool) {
+	for _, e := range o.entries {
+		if e.key == keyPrefix+id {
+			return e, true
+		}
+	}
+
+	return nil, false
+}
+
+// modifiable returns the entry, collection and raw collection value of
+// the collection with the specified ID.
+func (o *CloudStorage) modifiable(id string) (*entry, Collection, map[string]json.RawMessage, error) {
+	e, ok := o.entry(id)
+	if !ok {
+		return nil, Collection{}, nil, errors.New("the collection '" + id + "' does not exist")
+	}
+
+	collection, ok := e.collection()
+	if !ok {
+		return nil, Collection{}, nil, errors.New("the collection '" + id + "' was deleted")
+	}
+
+	value, err := e.value()
+	if err != nil {
+		return nil, Collection{}, nil, err
+	}
+
+	return e, collection, value, nil
+}
+
+// checkName returns a non-nil error if name cannot be used as the name
+// of the collection with the specified ID.
+func (o *CloudStorage) checkName(name string, id string) error {
+	if len(strings.TrimSpace(name)) == 0 {
+		return errors.New("please specify a collection name")
+	}
+
+	for _, collection := range o.Collections() {
+		if collection.Id != id && strings.EqualFold(collection.Name, name) {
+			return errors.New("a collection named '" + collection.Name + "' already exists")
+		}
+	}
+
+	return nil
+}
+
+// nextVersion returns a version that is newer than the version of every
+// entry in the file.
+func (o *CloudStorage) nextVersion() string {
+	var max uint64
+
+	for _, e := range o.entries {
+		var version string
+		if json.Unmarshal(e.fields[entryVersionKey], &version) != nil {
+			continue
+		}
+
+		v, err := strconv.ParseUint(version, 10, 64)
+		if err == nil && v > max {
+			max = v
+		}
+	}
+
+	return strconv.FormatUint(max+1, 10)
+}
+
+// collection returns the entry's collection. It returns false if the
+// entry is not a collection or if the collection was deleted.
+func (o *entry) collection() (Collection, bool) {
+	if !strings.HasPrefix(o.key, keyPrefix) {
+		return Collection{}, false
+	}
+
+	var isDeleted bool
+	json.Unmarshal(o.fields[entryIsDeletedKey], &isDeleted)
+	if isDeleted {
+		return Collection{}, false
+	}
+
+	value, err := o.value()
+	if err != nil {
+		return Collection{}, false
+	}
+
+	collection := Collection{
+		Id: strings.TrimPrefix(o.key, keyPrefix),
+	}
+
+	json.Unmarshal(value[collectionIdKey], &collection.Id)
+	json.Unmarshal(value[collectionNameKey], &collection.Name)
+
+	collection.AppIds = appIdsValue(value[collectionAddedKey])
+	collection.RemovedAppIds = appIdsValue(value[collectionRemovedKey])
+
+	_, collection.IsDynamic = value[collectionFilterSpecKey]
+
+	return collection, true
+}
+
+// value returns the entry's value, which is a JSON object encoded as a
+// JSON string.
+func (o *entry) value() (map[string]json.RawMessage, error) {
+	var encoded string
+
+	err := json.Unmarshal(o.fields[entryValueKey], &encoded)
+	if err != nil {
+		return nil, errors.New("the value of '" + o.key + "' is not a string - " + err.Error())
+	}
+
+	var value map[string]json.RawMessage
+
+	err = json.Unmarshal([]byte(encoded), &value)
+	if err != nil {
+		return nil, errors.New("failed to parse the value of '" + o.key + "' - " + err.Error())
+	}
+
+	return value, nil
+}
+
+// setCollection updates value using the collection's fields and stores it
+// in the entry. Fields of value that are not part of a Collection, such
+// as the filters of a dynamic collection, are preserved.
+func (o *entry) setCollection(collection Collection, value map[string]json.RawMessage) error {
+	fields := map[string]interface{}{
+		collectionIdKey:      collection.Id,
+		collectionNameKey:    collection.Name,
+		collectionAddedKey:   appIdsToNumbers(collection.AppIds),
+		collectionRemovedKey: appIdsToNumbers(collection.RemovedAppIds),
+	}
+
+	for key, v := range fields {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		value[key] = raw
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	o.setString(entryValueKey, string(encoded))
+	o.touch()
+
+	return nil
+}
+
+// touch updates the entry's timestamp. The version is left unchanged.
+func (o *entry) touch() {
+	o.fields[entryTimestampKey] = json.RawMessage(strconv.FormatInt(time.Now().Unix(), 10))
+}
+
+func (o *entry) setString(key string, value string) {
+	raw, _ := json.Marshal(value)
+	o.fields[key] = raw
+}
+
+// appIdsValue returns the app IDs in a JSON array of numbers. Invalid
+// app IDs are skipped.
+func appIdsValue(raw json.RawMessage) []string {
+	var numbers []json.Number
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	if decoder.Decode(&numbers) != nil {
+		return nil
+	}
+
+	var appIds []string
+
+	for _, number := range numbers {
+		if checkAppId(number.String()) == nil {
+			appIds = append(appIds, number.String())
+		}
+	}
+
+	return appIds
+}
+
+func appIdsToNumbers(appIds []string) []uint64 {
+	numbers := make([]uint64, 0, len(appIds))
+
+	for _, appId := range appIds {
+		n, err := strconv.ParseUint(appId, 10, 32)
+		if err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+
+	return numbers
+}
+
+func checkAppId(appId string) error {
+	id, err := strconv.ParseUint(appId, 10, 32)
+	if err != nil || id == 0 {
+		return errors.New("the app ID '" + appId + "' is not a valid app ID")
+	}
+
+	return nil
+}
+
+func shortcutAppIds(scs []shortcuts.Shortcut) []string {
+	appIds := make([]string, len(scs))
+
+	for i := range scs {
+		appIds[i] = scs[i].AppId()
+	}
+
+	return appIds
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeString(values []string, s string) []string {
+	var result []string
+
+	for _, v := range values {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// newCollectionId generates a random collection ID in the same format as
+// the IDs generated by Steam.
+func newCollectionId() (string, error) {
+	random := make([]byte, newIdLength)
+
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", errors.New("failed to generate collection ID - " + err.Error())
+	}
+
+	id := make([]byte, newIdLength)
+
+	for i, b := range random {
+		id[i] = newIdCharacters[int(b)%len(newIdCharacters)]
+	}
+
+	return newIdPrefix + string(id), nil
+}
+
+// Parse parses a cloud storage file.
+func Parse(r io.Reader) (*CloudStorage, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs [][]json.RawMessage
+
+	err = json.Unmarshal(raw, &pairs)
+	if err != nil {
+		return nil, errors.New("failed to parse cloud storage file - " + err.Error())
+	}
+
+	storage := &CloudStorage{}
+
+	for i, pair := range pairs {
+		e := &entry{}
+
+		if len(pair) != 2 || json.Unmarshal(pair[0], &e.key) != nil || json.Unmarshal(pair[1], &e.fields) != nil {
+			return nil, errors.New("cloud storage file entry " + strconv.Itoa(i) +
+				" is not a key and object pair")
+		}
+
+		if e.fields == nil {
+			e.fields = make(map[string]json.RawMessage)
+		}
+
+		storage.entries = append(storage.entries, e)
+	}
+
+	return storage, nil
+}
+
+// Read reads the cloud storage file of the specified Steam user.
+func Read(dv locations.DataVerifier, userId string) (*CloudStorage, error) {
+	filePath, err := filePath(dv, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := locations.FileSystemOf(dv).Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// WriteConfig configures the cloud storage file write operation.
+type WriteConfig struct {
+	// DataVerifier is used to get the cloud storage file path.
+	DataVerifier locations.DataVerifier
+
+	// OwnerUserId is the Steam user ID whose file is written.
+	OwnerUserId string
+
+	// CloudStorage is the cloud storage to write.
+	CloudStorage *CloudStorage
+
+	// Mode is the mode to set the file to if a new file is created.
+	// This defaults to 0644 if not specified.
+	Mode os.FileMode
+
+	// RefuseIfSteamRunning specifies whether or not the write should
+	// be refused while Steam is running. A running Steam client keeps
+	// its own copy of the collections, which it syncs with the Steam
+	// Cloud and saves over the cloud storage file.
+	RefuseIfSteamRunning bool
+}
+
+// Validate returns a non-nil error if the WriteConfig is invalid.
+func (o *WriteConfig) Validate() error {
+	if o.DataVerifier == nil {
+		return errors.New("the DataVerifier cannot be nil")
+	}
+
+	if o.CloudStorage == nil {
+		return errors.New("the CloudStorage cannot be nil")
+	}
+
+	return nil
+}
+
+// Write replaces the cloud storage file of the specified Steam user.
+func Write(config WriteConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	filePath, err := filePath(config.DataVerifier, config.OwnerUserId)
+	if err != nil {
+		return err
+	}
+
+	return locations.WriteDataFile(locations.WriteDataFileConfig{
+		DataVerifier:         config.DataVerifier,
+		FilePath:             filePath,
+		Mode:                 config.Mode,
+		RefuseIfSteamRunning: config.RefuseIfSteamRunning,
+		Write:                config.CloudStorage.Write,
+	})
+}
+
+func filePath(dv locations.DataVerifier, userId string) (string, error) {
+	if len(strings.TrimSpace(userId)) == 0 {
+		return "", errors.New("please specify a Steam user ID")
+	}
+
+	accountId, err := naming.ParseUserId(userId)
+	if err != nil {
+		return "", err
+	}
+
+	return locations.CollectionsFilePath(dv.RootDirPath(), accountId), nil
+}
@@ -0,0 +1,151 @@
+package collections
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/internal/steamtest"
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+const (
+	testUserId  = "12345678"
+	testFixture = "cloudstorage-json/cloud-storage-namespace-1.json"
+)
+
+func TestRead(t *testing.T) {
+	dv := steamtest.NewDataVerifier(t, locations.CollectionsFilePath("", testUserId), testFixture)
+
+	storage, err := Read(dv, "[U:1:"+testUserId+"]")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	collections := storage.Collections()
+
+	var ids []string
+	for _, collection := range collections {
+		ids = append(ids, collection.Id)
+	}
+
+	if strings.Join(ids, ",") != "favorite,hidden,uc-Dyn4mic00000,uc-AbCdEf123456" {
+		t.Fatal("Unexpected collections -", ids)
+	}
+
+	puzzle, ok := storage.Collection("uc-AbCdEf123456")
+	if !ok || puzzle.Name != "Puzzle" || strings.Join(puzzle.AppIds, ",") != "400,620,3000000000" || puzzle.IsDynamic {
+		t.Fatal("Unexpected collection -", puzzle)
+	}
+
+	installed, _ := storage.Collection("uc-Dyn4mic00000")
+	if !installed.IsDynamic || strings.Join(installed.RemovedAppIds, ",") != "220" {
+		t.Fatal("Unexpected dynamic collection -", installed)
+	}
+
+	_, ok = storage.Collection("uc-G0neG0neG0ne")
+	if ok {
+		t.Fatal("Deleted collection was returned")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dv := steamtest.NewDataVerifier(t, locations.CollectionsFilePath("", testUserId), testFixture)
+
+	storage, err := Read(dv, testUserId)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	s := shortcuts.Shortcut{
+		AppName: "Pikmin",
+		ExePath: "/games/pikmin.exe",
+	}
+
+	created, err := storage.CreateCollection("Emulated")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !strings.HasPrefix(created.Id, newIdPrefix) || len(created.Id) != len(newIdPrefix)+newIdLength {
+		t.Fatal("Unexpected collection ID - '" + created.Id + "'")
+	}
+
+	_, err = storage.CreateCollection("puzzle")
+	if err == nil {
+		t.Fatal("Expected an error when creating a collection with an existing name")
+	}
+
+	err = storage.AddShortcuts(created.Id, s)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = storage.RemoveApps("uc-Dyn4mic00000", "400")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = storage.AddApps("uc-Dyn4mic00000", "220")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = storage.RenameCollection("uc-AbCdEf123456", "Puzzles")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = storage.RenameCollection(FavoritesId, "Best")
+	if err == nil {
+		t.Fatal("Expected an error when renaming a built-in collection")
+	}
+
+	err = storage.DeleteCollection("uc-AbCdEf123456")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = Write(WriteConfig{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		CloudStorage: storage,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	storage, err = Read(dv, testUserId)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	emulated, ok := storage.Collection(created.Id)
+	if !ok || emulated.Name != "Emulated" || len(emulated.AppIds) != 1 || emulated.AppIds[0] != s.AppId() {
+		t.Fatal("Unexpected created collection -", emulated)
+	}
+
+	installed, _ := storage.Collection("uc-Dyn4mic00000")
+	if strings.Join(installed.AppIds, ",") != "220" || strings.Join(installed.RemovedAppIds, ",") != "400" {
+		t.Fatal("Unexpected dynamic collection -", installed)
+	}
+
+	_, ok = storage.Collection("uc-AbCdEf123456")
+	if ok {
+		t.Fatal("Collection was not deleted")
+	}
+
+	buffer := bytes.NewBuffer(nil)
+
+	err = storage.Write(buffer)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, preserved := range []string{`"showcases.0"`, `"version":"30"`, `"version":"32"`, `filterSpec`, `nFormatVersion`} {
+		if !strings.Contains(buffer.String(), preserved) {
+			t.Fatal("Expected file to contain '" + preserved + "' - " + buffer.String())
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package collections provides functionality for working with a Steam
+// user's collections, which current versions of Steam store in the user's
+// cloud storage file rather than in the tags of each shortcut.
+package collections
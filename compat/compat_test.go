@@ -0,0 +1,138 @@
+package compat
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/apps"
+	"github.com/stephen-fox/steamutil/internal/steamtest"
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+const (
+	testFixture = "config-vdf/config.vdf"
+)
+
+func TestConfigFile_SetShortcutTool(t *testing.T) {
+	dv := steamtest.NewDataVerifier(t, locations.ConfigFilePath(""), testFixture)
+
+	cf, err := Read(dv)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	mappings := cf.Mappings()
+	if len(mappings) != 1 || mappings[0].Name != "proton_experimental" || mappings[0].Priority != "75" {
+		t.Fatal("Unexpected mappings -", mappings)
+	}
+
+	s := shortcuts.Shortcut{
+		AppName: "Pikmin",
+		ExePath: "/games/pikmin.exe",
+	}
+
+	err = cf.SetShortcutTool(s, "GE-Proton8-25")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = Write(WriteConfig{
+		DataVerifier: dv,
+		ConfigFile:   cf,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	cf, err = Read(dv)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	mapping, ok := cf.Mapping(s.AppId())
+	if !ok || mapping.Name != "GE-Proton8-25" || mapping.Priority != DefaultPriority {
+		t.Fatal("Unexpected mapping for shortcut -", mapping)
+	}
+
+	buffer := bytes.NewBuffer(nil)
+
+	err = cf.Write(buffer)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, preserved := range []string{`"SDL_GamepadBind"`, `"76561197972611406"`, `"1245620"`} {
+		if !strings.Contains(buffer.String(), preserved) {
+			t.Fatal("Unrelated data was not preserved - " + preserved)
+		}
+	}
+
+	if !cf.RemoveMapping(s.AppId()) {
+		t.Fatal("Mapping was not removed")
+	}
+
+	err = cf.SetMapping(Mapping{AppId: "400"})
+	if err == nil {
+		t.Fatal("Expected an error for a mapping without a tool name")
+	}
+}
+
+func TestTools(t *testing.T) {
+	dv := steamtest.NewDataVerifier(t, locations.ConfigFilePath(""), testFixture)
+
+	toolDirPath := path.Join(ToolsDirPath(dv.RootDirPath()), "GE-Proton8-25")
+
+	err := os.MkdirAll(toolDirPath, 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = ioutil.WriteFile(path.Join(toolDirPath, compatToolFileName), []byte(`"compatibilitytools"
+{
+	"compat_tools"
+	{
+		"GE-Proton8-25"
+		{
+			"install_path"		"."
+			"display_name"		"GE-Proton 8-25"
+			"from_oslist"		"windows"
+			"to_oslist"		"linux"
+		}
+	}
+}
+`), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	installed := []apps.Manifest{
+		{AppId: "2348590", Name: "Proton 8.0", StateFlags: apps.StateFullyInstalled},
+		{AppId: "1420170", Name: "Proton 5.13", StateFlags: apps.StateFullyInstalled},
+		{AppId: "1493710", Name: "Proton Experimental", StateFlags: apps.StateFullyInstalled},
+		{AppId: "1826330", Name: "Proton EasyAntiCheat Runtime", StateFlags: apps.StateFullyInstalled},
+		{AppId: "400", Name: "Portal", StateFlags: apps.StateFullyInstalled},
+	}
+
+	tools, err := Tools(dv, installed)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+
+	if strings.Join(names, ",") != "GE-Proton8-25,proton_513,proton_8,proton_experimental" {
+		t.Fatal("Unexpected tools -", names)
+	}
+
+	if tools[0].DisplayName != "GE-Proton 8-25" || tools[0].Kind != CustomTool || tools[0].DirPath != toolDirPath {
+		t.Fatal("Unexpected custom tool -", tools[0])
+	}
+}
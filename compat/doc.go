@@ -0,0 +1,3 @@
+// Package compat provides functionality for working with Steam Play
+// compatibility tools, such as Proton, which run Windows games on Linux.
+package compat
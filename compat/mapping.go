@@ -0,0 +1,256 @@
+package compat
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+const (
+	// DefaultPriority is the priority that Steam assigns to compatibility
+	// tools that are chosen by the user.
+	DefaultPriority = "250"
+
+	rootKey     = "InstallConfigStore"
+	nameKey     = "name"
+	configKey   = "config"
+	priorityKey = "priority"
+)
+
+var (
+	mappingKeys = []string{"Software", "Valve", "Steam", "CompatToolMapping"}
+)
+
+// Mapping assigns a compatibility tool to an app.
+type Mapping struct {
+	// AppId is the app ID of the Steam app or non-Steam shortcut.
+	AppId string
+
+	// Name is the internal name of the compatibility tool, such as
+	// 'proton_8' or 'GE-Proton8-25'.
+	Name string
+
+	// Config is an optional tool-specific configuration string.
+	Config string
+
+	// Priority is the priority of the mapping. This defaults to
+	// DefaultPriority when the mapping is set.
+	Priority string
+}
+
+// ConfigFile is a parsed Steam configuration file (config.vdf). Everything
+// in the file is preserved when it is written, including the sections that
+// are not exposed by ConfigFile.
+type ConfigFile struct {
+	doc *vdf.KeyValue
+}
+
+// Mappings returns every compatibility tool mapping in the file, sorted
+// by app ID. Entries without a tool name, which Steam uses to record
+// that the default tool is used, are excluded.
+func (o *ConfigFile) Mappings() []Mapping {
+	mappings, ok := o.mappings(false)
+	if !ok {
+		return nil
+	}
+
+	var result []Mapping
+
+	for _, entry := range mappings.Children {
+		mapping, ok := mappingFromKeyValue(entry)
+		if ok {
+			result = append(result, mapping)
+		}
+	}
+
+	sort.Slice(result, func(i int, j int) bool {
+		a, _ := strconv.ParseUint(result[i].AppId, 10, 64)
+		b, _ := strconv.ParseUint(result[j].AppId, 10, 64)
+		return a < b
+	})
+
+	return result
+}
+
+// Mapping returns the compatibility tool mapping for the specified app ID.
+func (o *ConfigFile) Mapping(appId string) (Mapping, bool) {
+	mappings, ok := o.mappings(false)
+	if !ok {
+		return Mapping{}, false
+	}
+
+	entry, ok := mappings.Get(appId)
+	if !ok {
+		return Mapping{}, false
+	}
+
+	return mappingFromKeyValue(entry)
+}
+
+// SetMapping creates or replaces the compatibility tool mapping for
+// the mapping's app ID.
+func (o *ConfigFile) SetMapping(mapping Mapping) error {
+	id, err := strconv.ParseUint(mapping.AppId, 10, 32)
+	if err != nil || id == 0 {
+		return errors.New("the app ID '" + mapping.AppId + "' is not a valid app ID")
+	}
+
+	if len(mapping.Name) == 0 {
+		return errors.New("please specify a compatibility tool name")
+	}
+
+	if len(mapping.Priority) == 0 {
+		mapping.Priority = DefaultPriority
+	}
+
+	mappings, _ := o.mappings(true)
+
+	entry := mappings.SetObject(mapping.AppId)
+	entry.Set(nameKey, mapping.Name)
+	entry.Set(configKey, mapping.Config)
+	entry.Set(priorityKey, mapping.Priority)
+
+	return nil
+}
+
+// SetShortcutTool sets the compatibility tool for a non-Steam shortcut.
+func (o *ConfigFile) SetShortcutTool(s shortcuts.Shortcut, toolName string) error {
+	return o.SetMapping(Mapping{
+		AppId: s.AppId(),
+		Name:  toolName,
+	})
+}
+
+// RemoveMapping removes the compatibility tool mapping for the specified
+// app ID. It returns true if a mapping was removed.
+func (o *ConfigFile) RemoveMapping(appId string) bool {
+	mappings, ok := o.mappings(false)
+	if !ok {
+		return false
+	}
+
+	return mappings.Remove(appId)
+}
+
+// Write writes the configuration file to the specified io.Writer.
+func (o *ConfigFile) Write(w io.Writer) error {
+	return vdf.WriteText(w, o.doc)
+}
+
+// mappings returns the object containing the compatibility tool mappings.
+// If create is true, the object is created if it does not exist.
+func (o *ConfigFile) mappings(create bool) (*vdf.KeyValue, bool) {
+	if !create {
+		return o.doc.Lookup(append([]string{rootKey}, mappingKeys...)...)
+	}
+
+	current := o.doc.SetObject(rootKey)
+
+	for _, key := range mappingKeys {
+		current = current.SetObject(key)
+	}
+
+	return current, true
+}
+
+func mappingFromKeyValue(entry *vdf.KeyValue) (Mapping, bool) {
+	if !entry.IsObject {
+		return Mapping{}, false
+	}
+
+	mapping := Mapping{
+		AppId: entry.Key,
+	}
+
+	mapping.Name, _ = entry.StringValue(nameKey)
+	mapping.Config, _ = entry.StringValue(configKey)
+	mapping.Priority, _ = entry.StringValue(priorityKey)
+
+	if len(mapping.Name) == 0 {
+		return Mapping{}, false
+	}
+
+	return mapping, true
+}
+
+// Parse parses a Steam configuration file.
+func Parse(r io.Reader) (*ConfigFile, error) {
+	doc, err := vdf.ParseText(r)
+	if err != nil {
+		return nil, errors.New("failed to parse configuration file - " + err.Error())
+	}
+
+	root, ok := doc.Get(rootKey)
+	if !ok || !root.IsObject {
+		return nil, errors.New("the configuration file is missing its '" + rootKey + "' object")
+	}
+
+	return &ConfigFile{
+		doc: doc,
+	}, nil
+}
+
+// Read reads the Steam configuration file of the specified data directory.
+func Read(dv locations.DataVerifier) (*ConfigFile, error) {
+	f, err := locations.FileSystemOf(dv).Open(locations.ConfigFilePath(dv.RootDirPath()))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// WriteConfig configures the configuration file write operation.
+type WriteConfig struct {
+	// DataVerifier is used to get the configuration file path.
+	DataVerifier locations.DataVerifier
+
+	// ConfigFile is the configuration to write.
+	ConfigFile *ConfigFile
+
+	// Mode is the mode to set the file to if a new file is created.
+	// This defaults to 0644 if not specified.
+	Mode os.FileMode
+
+	// RefuseIfSteamRunning specifies whether or not the write should
+	// be refused while Steam is running. Steam keeps the compatibility
+	// tool mapping in memory and saves it to config.vdf when it exits,
+	// replacing any mapping written in the meantime.
+	RefuseIfSteamRunning bool
+}
+
+// Validate returns a non-nil error if the WriteConfig is invalid.
+func (o *WriteConfig) Validate() error {
+	if o.DataVerifier == nil {
+		return errors.New("the DataVerifier cannot be nil")
+	}
+
+	if o.ConfigFile == nil {
+		return errors.New("the ConfigFile cannot be nil")
+	}
+
+	return nil
+}
+
+// Write replaces the Steam configuration file.
+func Write(config WriteConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	return locations.WriteDataFile(locations.WriteDataFileConfig{
+		DataVerifier:         config.DataVerifier,
+		FilePath:             locations.ConfigFilePath(config.DataVerifier.RootDirPath()),
+		Mode:                 config.Mode,
+		RefuseIfSteamRunning: config.RefuseIfSteamRunning,
+		Write:                config.ConfigFile.Write,
+	})
+}
@@ -0,0 +1,195 @@
+package compat
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stephen-fox/steamutil/apps"
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+const (
+	// CustomTool is a compatibility tool that was installed manually,
+	// such as a community build of Proton.
+	CustomTool ToolKind = "custom"
+
+	// SteamTool is a compatibility tool that was installed by Steam
+	// as an app, such as an official Proton release.
+	SteamTool ToolKind = "steam"
+)
+
+const (
+	compatToolsDirName  = "compatibilitytools.d"
+	compatToolFileName  = "compatibilitytool.vdf"
+	protonAppNamePrefix = "proton"
+)
+
+var (
+	protonVersionPattern = regexp.MustCompile(`^(\d+)\.(\d+)$`)
+)
+
+// ToolKind describes how a compatibility tool was installed.
+type ToolKind string
+
+// Tool is a compatibility tool that can be assigned to an app.
+type Tool struct {
+	// Name is the internal name of the tool, which is used in
+	// a Mapping.
+	Name string
+
+	// DisplayName is the name of the tool that Steam displays.
+	DisplayName string
+
+	// DirPath is the path to the tool's directory.
+	DirPath string
+
+	// Kind describes how the tool was installed.
+	Kind ToolKind
+
+	// AppId is the app ID of the tool if it was installed by Steam.
+	AppId string
+}
+
+// Tools returns the compatibility tools that are available. Custom tools
+// are found in the data directory's 'compatibilitytools.d' directory.
+// Tools installed by Steam are found in the specified app manifests,
+// which are usually those returned by apps.Installed.
+//
+// Steam does not store the internal names of the tools that it installs
+// in their app manifests, so the names of SteamTool tools are derived
+// from their app names (for example, 'Proton 8.0' becomes 'proton_8').
+func Tools(dv locations.DataVerifier, installed []apps.Manifest) ([]Tool, error) {
+	tools, err := customTools(dv)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, manifest := range installed {
+		name, ok := steamToolName(manifest.Name)
+		if !ok || !manifest.StateFlags.IsInstalled() {
+			continue
+		}
+
+		tools = append(tools, Tool{
+			Name:        name,
+			DisplayName: manifest.Name,
+			DirPath:     manifest.InstallDirPath,
+			Kind:        SteamTool,
+			AppId:       manifest.AppId,
+		})
+	}
+
+	sort.SliceStable(tools, func(i int, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+
+	return tools, nil
+}
+
+// customTools returns the tools in the data directory's compatibility
+// tools directory. Directories without a valid tool description
+// are skipped.
+func customTools(dv locations.DataVerifier) ([]Tool, error) {
+	fs := locations.FileSystemOf(dv)
+	toolsDirPath := ToolsDirPath(dv.RootDirPath())
+
+	infos, err := fs.ReadDir(toolsDirPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var tools []Tool
+
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+
+		toolDirPath := path.Join(toolsDirPath, info.Name())
+
+		f, err := fs.Open(path.Join(toolDirPath, compatToolFileName))
+		if err != nil {
+			continue
+		}
+
+		doc, err := vdf.ParseText(f)
+		f.Close()
+		if err != nil {
+			continue
+		}
+
+		entries, ok := doc.Lookup("compatibilitytools", "compat_tools")
+		if !ok {
+			continue
+		}
+
+		for _, entry := range entries.Children {
+			if !entry.IsObject {
+				continue
+			}
+
+			tool := Tool{
+				Name:    entry.Key,
+				DirPath: toolDirPath,
+				Kind:    CustomTool,
+			}
+
+			tool.DisplayName, _ = entry.StringValue("display_name")
+			if len(tool.DisplayName) == 0 {
+				tool.DisplayName = entry.Key
+			}
+
+			installPath, _ := entry.StringValue("install_path")
+			if len(installPath) > 0 && installPath != "." {
+				if path.IsAbs(installPath) {
+					tool.DirPath = installPath
+				} else {
+					tool.DirPath = path.Join(toolDirPath, installPath)
+				}
+			}
+
+			tools = append(tools, tool)
+		}
+	}
+
+	return tools, nil
+}
+
+// steamToolName derives the internal name of a compatibility tool that
+// was installed by Steam from its app name. It returns false if the app
+// is not a compatibility tool.
+func steamToolName(appName string) (string, bool) {
+	name := strings.ToLower(strings.TrimSpace(appName))
+
+	if !strings.HasPrefix(name, protonAppNamePrefix+" ") || strings.HasSuffix(name, " runtime") {
+		return "", false
+	}
+
+	version := strings.TrimSpace(strings.TrimPrefix(name, protonAppNamePrefix))
+	version = strings.TrimPrefix(version, "- ")
+
+	matches := protonVersionPattern.FindStringSubmatch(version)
+	if matches != nil {
+		if matches[2] == "0" {
+			return protonAppNamePrefix + "_" + matches[1], true
+		}
+
+		return protonAppNamePrefix + "_" + matches[1] + matches[2], true
+	}
+
+	return protonAppNamePrefix + "_" + strings.Replace(version, " ", "_", -1), true
+}
+
+// ToolsDirPath generates a path to the directory containing custom
+// compatibility tools for the specified data directory.
+func ToolsDirPath(dataDirPath string) string {
+	return path.Join(dataDirPath, compatToolsDirName)
+}
@@ -13,6 +13,7 @@ func main() {
 	imagePath := flag.String("i", "", "The path to the image to add")
 	gameName := flag.String("g", "", "The name of the game that the image is for")
 	gameExePath := flag.String("e", "", "The game's executable path")
+	appId := flag.String("a", "", "The Steam app ID of the game (overrides -g and -e)")
 
 	flag.Parse()
 
@@ -28,26 +29,36 @@ func main() {
 
 	resultDetails := grid.ImageDetails{
 		DataVerifier:       dv,
+		AppId:              *appId,
 		GameName:           *gameName,
 		GameExecutablePath: *gameExePath,
 	}
 
-	userIdsToDirPaths, err := dv.UserIdsToDataDirPaths()
+	addConfig := grid.AddConfig{
+		ResultDetails:   resultDetails,
+		ImageSourcePath: *imagePath,
+	}
+
+	results, err := grid.AddImageForUsers(addConfig, nil)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	for userId := range userIdsToDirPaths {
-		resultDetails.OwnerUserId = userId
+	failed := false
 
-		addConfig := grid.AddConfig{
-			ResultDetails:   resultDetails,
-			ImageSourcePath: *imagePath,
+	for userId, result := range results {
+		if result.Err != nil {
+			log.Println("Failed to add image for user", userId, "-", result.Err.Error())
+			failed = true
+			continue
 		}
 
-		err := grid.AddImage(addConfig)
-		if err != nil {
-			log.Fatal(err.Error())
+		for _, p := range result.Paths {
+			log.Println("Added", p)
 		}
 	}
+
+	if failed {
+		os.Exit(1)
+	}
 }
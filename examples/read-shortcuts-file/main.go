@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -11,6 +12,9 @@ import (
 )
 
 func main() {
+	printJson := flag.Bool("j", false, "Print the shortcuts as JSON")
+	flag.Parse()
+
 	dv, err := locations.NewDataVerifier()
 	if err != nil {
 		log.Fatal(err.Error())
@@ -50,6 +54,15 @@ func main() {
 		log.Fatal(err.Error())
 	}
 
+	if *printJson {
+		err := shortcuts.WriteJSON(scs, os.Stdout)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+
+		return
+	}
+
 	for _, s := range scs {
 		fmt.Println("Application name:", s.AppName)
 		fmt.Println("Start dir:", s.StartDir)
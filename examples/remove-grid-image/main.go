@@ -31,21 +31,29 @@ func main() {
 		GameExecutablePath: *gameExePath,
 	}
 
-	userIdsToDirPaths, err := dv.UserIdsToDataDirPaths()
+	removeConfig := grid.RemoveConfig{
+		TargetDetails: targetDetails,
+	}
+
+	results, err := grid.RemoveImageForUsers(removeConfig, nil)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	for userId := range userIdsToDirPaths {
-		targetDetails.OwnerUserId = userId
+	failed := false
 
-		removeConfig := grid.RemoveConfig{
-			TargetDetails: targetDetails,
+	for userId, result := range results {
+		for _, p := range result.Paths {
+			log.Println("Removed", p)
 		}
 
-		err := grid.RemoveImage(removeConfig)
-		if err != nil {
-			log.Fatal(err.Error())
+		if result.Err != nil {
+			log.Println("Failed to remove images for user", userId, "-", result.Err.Error())
+			failed = true
 		}
 	}
+
+	if failed {
+		os.Exit(1)
+	}
 }
@@ -41,4 +41,14 @@ func main() {
 				shortcutPath, "- Size:", info.Size())
 		}
 	}
+
+	// Get the users that have logged in to Steam:
+	users, err := locations.Users(dv)
+	if err != nil {
+		log.Fatal("Failed to get users - " + err.Error())
+	}
+	for _, user := range users {
+		log.Println("User:", user.PersonaName, "- Account ID:", user.AccountId,
+			"- SteamID64:", user.SteamId.String(), "- Most recent:", user.MostRecent)
+	}
 }
@@ -13,6 +13,7 @@ import (
 func main() {
 	filePath := flag.String("f", "", "The path to the shortcuts file to modify")
 	gameName := flag.String("n", "", "The name of the game to modify")
+	checkRunning := flag.Bool("c", false, "Refuse to modify the file if Steam is running")
 	flag.Parse()
 
 	if len(os.Args) == 1 {
@@ -45,10 +46,11 @@ func main() {
 	}
 
 	config := shortcuts.CreateOrUpdateConfig{
-		Path:      *filePath,
-		MatchName: *gameName,
-		OnMatch:   onMatch,
-		NoMatch:   noMatch,
+		Path:                 *filePath,
+		MatchName:            *gameName,
+		OnMatch:              onMatch,
+		NoMatch:              noMatch,
+		RefuseIfSteamRunning: *checkRunning,
 	}
 
 	result, err := shortcuts.CreateOrUpdateFile(config)
@@ -1,3 +1,5 @@
 module github.com/stephen-fox/steamutil
 
-require golang.org/x/sys v0.0.0-20181218192612-074acd46bca6
+go 1.16
+
+require golang.org/x/sys v0.0.0-20210423082822-04245dca01da
@@ -0,0 +1,606 @@
+package grid
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+const (
+	// TarGzArchive is a gzip compressed tar archive.
+	TarGzArchive ArchiveFormat = "tar.gz"
+
+	// ZipArchive is a zip archive.
+	ZipArchive ArchiveFormat = "zip"
+)
+
+const (
+	manifestFileName       = "manifest.json"
+	archiveGridDirName     = "grid"
+	currentManifestVersion = 1
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+)
+
+// ArchiveFormat is the file format of a grid backup archive.
+type ArchiveFormat string
+
+// Manifest describes the contents of a grid backup archive.
+type Manifest struct {
+	// Version is the manifest format version.
+	Version int `json:"version"`
+
+	// OwnerUserId is the Steam user ID that the backup was made for.
+	OwnerUserId string `json:"owner_user_id"`
+
+	// CreatedEpoch is when the backup was made, in seconds since
+	// the Unix epoch.
+	CreatedEpoch int64 `json:"created_epoch"`
+
+	// Files describes each file in the backup.
+	Files []ManifestFile `json:"files"`
+}
+
+// ManifestFile describes a single file in a grid backup archive.
+type ManifestFile struct {
+	// Name is the file's name in the grid directory.
+	Name string `json:"name"`
+
+	// Id is the game ID portion of the file name.
+	Id string `json:"id,omitempty"`
+
+	// IdKind is the kind of game ID, as returned by IdKind.String.
+	IdKind string `json:"id_kind,omitempty"`
+
+	// ShortcutName is the name of the shortcut that the file
+	// belonged to, if any.
+	ShortcutName string `json:"shortcut_name,omitempty"`
+
+	// ShortcutExePath is the executable path of the shortcut that the
+	// file belonged to, if any.
+	ShortcutExePath string `json:"shortcut_exe_path,omitempty"`
+
+	// AppId is the app ID of the shortcut that the file belonged to,
+	// or the Steam app ID that the file is for.
+	AppId string `json:"app_id,omitempty"`
+}
+
+// ExportConfig configures the grid backup operation.
+type ExportConfig struct {
+	// DataVerifier is used to get the grid images directory and
+	// shortcuts file paths.
+	DataVerifier locations.DataVerifier
+
+	// OwnerUserId is the Steam user ID whose grid directory is
+	// backed up.
+	OwnerUserId string
+
+	// Format is the archive format. Defaults to TarGzArchive.
+	Format ArchiveFormat
+
+	// Writer is where the archive is written to.
+	Writer io.Writer
+}
+
+// Validate returns a non-nil error if the ExportConfig is invalid.
+func (o *ExportConfig) Validate() error {
+	if o.DataVerifier == nil {
+		return errors.New("the DataVerifier cannot be nil")
+	}
+
+	ownerUserId, err := parseOwnerUserId(o.OwnerUserId)
+	if err != nil {
+		return err
+	}
+	o.OwnerUserId = ownerUserId
+
+	if o.Writer == nil {
+		return errors.New("the archive writer cannot be nil")
+	}
+
+	switch o.Format {
+	case "":
+		o.Format = TarGzArchive
+	case TarGzArchive, ZipArchive:
+		break
+	default:
+		return errors.New("unsupported archive format '" + string(o.Format) + "'")
+	}
+
+	return nil
+}
+
+// archiveWriter abstracts the differences between tar.gz and zip archives.
+type archiveWriter interface {
+	add(name string, data []byte, mode os.FileMode, modTime time.Time) error
+	Close() error
+}
+
+type tarGzWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (o *tarGzWriter) add(name string, data []byte, mode os.FileMode, modTime time.Time) error {
+	err := o.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     int64(mode.Perm()),
+		ModTime:  modTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = o.tw.Write(data)
+	return err
+}
+
+func (o *tarGzWriter) Close() error {
+	err := o.tw.Close()
+	if err != nil {
+		return err
+	}
+
+	return o.gz.Close()
+}
+
+type zipWriter struct {
+	zw *zip.Writer
+}
+
+func (o *zipWriter) add(name string, data []byte, mode os.FileMode, modTime time.Time) error {
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modTime,
+	}
+	header.SetMode(mode)
+
+	w, err := o.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+func (o *zipWriter) Close() error {
+	return o.zw.Close()
+}
+
+// Export writes a user's grid directory, including logo position files,
+// to an archive. The archive contains a manifest that maps each file to
+// the shortcut or app that it belongs to. Files whose names do not follow
+// Steam's naming conventions are not included.
+func Export(config ExportConfig) (Manifest, error) {
+	err := config.Validate()
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	inventory, err := TakeInventory(InventoryConfig{
+		DataVerifier: config.DataVerifier,
+		OwnerUserId:  config.OwnerUserId,
+	})
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	manifest := Manifest{
+		Version:      currentManifestVersion,
+		OwnerUserId:  config.OwnerUserId,
+		CreatedEpoch: time.Now().Unix(),
+	}
+
+	var filePaths []string
+
+	for _, entry := range inventory.Entries {
+		file := ManifestFile{
+			Name:   entry.Name,
+			Id:     entry.Id,
+			IdKind: entry.IdKind.String(),
+		}
+
+		switch {
+		case entry.Shortcut != nil:
+			file.ShortcutName = entry.Shortcut.AppName
+			file.ShortcutExePath = entry.Shortcut.ExePath
+			file.AppId = entry.Shortcut.AppId()
+		case entry.IdKind == SteamAppId:
+			file.AppId = entry.Id
+		}
+
+		manifest.Files = append(manifest.Files, file)
+		filePaths = append(filePaths, entry.Path)
+	}
+
+	var aw archiveWriter
+
+	switch config.Format {
+	case ZipArchive:
+		aw = &zipWriter{
+			zw: zip.NewWriter(config.Writer),
+		}
+	default:
+		gz := gzip.NewWriter(config.Writer)
+		aw = &tarGzWriter{
+			gz: gz,
+			tw: tar.NewWriter(gz),
+		}
+	}
+
+	rawManifest, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	err = aw.add(manifestFileName, rawManifest, defaultImageMode, time.Unix(manifest.CreatedEpoch, 0))
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	for _, p := range filePaths {
+		info, err := locations.FileSystemOf(config.DataVerifier).Stat(p)
+		if err != nil {
+			return Manifest{}, err
+		}
+
+		data, err := locations.FileSystemOf(config.DataVerifier).ReadFile(p)
+		if err != nil {
+			return Manifest{}, err
+		}
+
+		err = aw.add(archiveGridDirName+"/"+path.Base(p), data, info.Mode(), info.ModTime())
+		if err != nil {
+			return Manifest{}, err
+		}
+	}
+
+	err = aw.Close()
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return manifest, nil
+}
+
+// RestoreConfig configures the grid restore operation.
+type RestoreConfig struct {
+	// DataVerifier is used to get the grid images directory and
+	// shortcuts file paths.
+	DataVerifier locations.DataVerifier
+
+	// OwnerUserId is the Steam user ID whose grid directory is
+	// restored to.
+	OwnerUserId string
+
+	// Reader provides the archive. The archive's format is
+	// detected from its content.
+	Reader io.Reader
+
+	// OverwriteExisting specifies whether or not existing files in the
+	// grid directory should be overwritten.
+	OverwriteExisting bool
+
+	// Remap specifies whether or not files that belonged to a shortcut
+	// should be renamed when the shortcut's name or executable has
+	// changed since the backup was made. Shortcuts are matched by
+	// name, and then by executable path. Remapped files are named
+	// using the shortcut's current app ID.
+	Remap bool
+
+	// Mode specifies the os.FileMode for restored files.
+	// If not specified, defaultImageMode will be used.
+	Mode os.FileMode
+}
+
+// Validate returns a non-nil error if the RestoreConfig is invalid.
+func (o *RestoreConfig) Validate() error {
+	if o.DataVerifier == nil {
+		return errors.New("the DataVerifier cannot be nil")
+	}
+
+	ownerUserId, err := parseOwnerUserId(o.OwnerUserId)
+	if err != nil {
+		return err
+	}
+	o.OwnerUserId = ownerUserId
+
+	if o.Reader == nil {
+		return errors.New("the archive reader cannot be nil")
+	}
+
+	if o.Mode == 0 {
+		o.Mode = defaultImageMode
+	}
+
+	return nil
+}
+
+// RestoreResult describes the outcome of a restore operation.
+type RestoreResult struct {
+	// Manifest is the archive's manifest.
+	Manifest Manifest
+
+	// Restored are the paths of the files that were written.
+	Restored []string
+
+	// Remapped maps the original file names of remapped files to
+	// their new file names.
+	Remapped map[string]string
+
+	// Skipped are the names of files that were not restored because
+	// a file with the same name already exists.
+	Skipped []string
+}
+
+// Restore restores a grid backup archive created by Export to a user's
+// grid directory. The grid directory is created if it does not exist.
+func Restore(config RestoreConfig) (RestoreResult, error) {
+	err := config.Validate()
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	manifest, files, err := readArchive(config.Reader)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	err = ensureGridDir(config.DataVerifier, config.OwnerUserId)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	gridDirPath, _, err := config.DataVerifier.GridDirPath(config.OwnerUserId)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	var scs []shortcuts.Shortcut
+
+	if config.Remap {
+		scs, err = readUserShortcuts(config.DataVerifier, config.OwnerUserId)
+		if err != nil {
+			return RestoreResult{}, err
+		}
+	}
+
+	fs := locations.FileSystemOf(config.DataVerifier)
+
+	result := RestoreResult{
+		Manifest: manifest,
+		Remapped: make(map[string]string),
+	}
+
+	for _, file := range manifest.Files {
+		if !isValidGridFileName(file.Name) {
+			return result, errors.New("the archive contains an invalid file name '" + file.Name + "'")
+		}
+
+		data, ok := files[file.Name]
+		if !ok {
+			return result, errors.New("the archive is missing '" + file.Name + "'")
+		}
+
+		name := file.Name
+
+		if config.Remap {
+			newName, remapped := remapFileName(file, scs)
+			if remapped {
+				result.Remapped[name] = newName
+				name = newName
+			}
+		}
+
+		filePath := path.Join(gridDirPath, name)
+
+		if !config.OverwriteExisting {
+			_, statErr := fs.Stat(filePath)
+			if statErr == nil {
+				result.Skipped = append(result.Skipped, name)
+				continue
+			}
+		}
+
+		err := fs.WriteFile(filePath, data, config.Mode)
+		if err != nil {
+			return result, err
+		}
+
+		result.Restored = append(result.Restored, filePath)
+	}
+
+	return result, nil
+}
+
+// remapFileName returns the new name for a backed up file if the
+// shortcut that it belonged to has changed.
+func remapFileName(file ManifestFile, scs []shortcuts.Shortcut) (string, bool) {
+	if len(file.ShortcutName) == 0 && len(file.ShortcutExePath) == 0 {
+		return "", false
+	}
+
+	entry, ok := ParseFileName(file.Name)
+	if !ok {
+		return "", false
+	}
+
+	target, found := findShortcut(file, scs)
+	if !found {
+		return "", false
+	}
+
+	if target.AppId() == entry.Id || target.LegacyId() == entry.Id {
+		return "", false
+	}
+
+	if entry.IsLogoPosition {
+		return target.AppId() + entry.Extension, true
+	}
+
+	return target.AppId() + entry.Slot.Suffix() + entry.Extension, true
+}
+
+func findShortcut(file ManifestFile, scs []shortcuts.Shortcut) (shortcuts.Shortcut, bool) {
+	if len(file.ShortcutName) > 0 {
+		for _, s := range scs {
+			if s.AppName == file.ShortcutName {
+				return s, true
+			}
+		}
+	}
+
+	if len(file.ShortcutExePath) > 0 {
+		for _, s := range scs {
+			if s.ExePath == file.ShortcutExePath {
+				return s, true
+			}
+		}
+	}
+
+	return shortcuts.Shortcut{}, false
+}
+
+// readArchive reads a grid backup archive into memory. It returns the
+// archive's manifest, and a map of grid file names to their contents.
+func readArchive(r io.Reader) (Manifest, map[string][]byte, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+
+	files := make(map[string][]byte)
+
+	switch {
+	case bytes.HasPrefix(raw, gzipMagic):
+		err = readTarGz(raw, files)
+	case bytes.HasPrefix(raw, zipMagic):
+		err = readZip(raw, files)
+	default:
+		err = errors.New("the archive is not a tar.gz or zip file")
+	}
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+
+	rawManifest, ok := files[manifestFileName]
+	if !ok {
+		return Manifest{}, nil, errors.New("the archive does not contain a " + manifestFileName)
+	}
+
+	var manifest Manifest
+
+	err = json.Unmarshal(rawManifest, &manifest)
+	if err != nil {
+		return Manifest{}, nil, errors.New("failed to parse " + manifestFileName + " - " + err.Error())
+	}
+
+	gridFiles := make(map[string][]byte)
+
+	for name, data := range files {
+		if !strings.HasPrefix(name, archiveGridDirName+"/") {
+			continue
+		}
+
+		name = strings.TrimPrefix(name, archiveGridDirName+"/")
+		if !isValidGridFileName(name) {
+			return Manifest{}, nil, errors.New("the archive contains an invalid file name '" + name + "'")
+		}
+
+		gridFiles[name] = data
+	}
+
+	return manifest, gridFiles, nil
+}
+
+// isValidGridFileName returns true if name is the name of a file in the
+// grid directory, rather than a path, and follows Steam's naming
+// conventions. Both slashes and backslashes are rejected, as either may
+// separate path elements depending on the operating system.
+func isValidGridFileName(name string) bool {
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return false
+	}
+
+	_, ok := ParseFileName(name)
+
+	return ok
+}
+
+func readTarGz(raw []byte, files map[string][]byte) error {
+	gz, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+
+		files[path.Clean(header.Name)] = data
+	}
+}
+
+func readZip(raw []byte, files map[string][]byte) error {
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+
+		files[path.Clean(f.Name)] = data
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package grid
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+func TestExportAndRestore(t *testing.T) {
+	for _, format := range []ArchiveFormat{TarGzArchive, ZipArchive} {
+		testExportAndRestore(t, format)
+	}
+}
+
+func testExportAndRestore(t *testing.T, format ArchiveFormat) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	original := shortcuts.Shortcut{
+		AppName: "Chess",
+		ExePath: "/Applications/Chess.app",
+	}
+
+	writeTestShortcuts(t, dv, []shortcuts.Shortcut{original})
+
+	gridDirPath := locations.GridDirPath(dv.RootDirPath(), testUserId)
+
+	names := []string{
+		original.AppId() + "_hero.png",
+		original.AppId() + ".json",
+		"400p.png",
+	}
+
+	unrecognizedPath := path.Join(gridDirPath, "notes.txt")
+
+	err := ioutil.WriteFile(unrecognizedPath, []byte("notes"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, name := range names {
+		err := ioutil.WriteFile(path.Join(gridDirPath, name), []byte(name), 0600)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	archive := bytes.NewBuffer(nil)
+
+	manifest, err := Export(ExportConfig{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		Format:       format,
+		Writer:       archive,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(manifest.Files) != len(names) {
+		t.Fatal("Unexpected number of manifest files -", len(manifest.Files))
+	}
+
+	for _, file := range manifest.Files {
+		if file.Name == names[0] && (file.ShortcutName != original.AppName || file.AppId != original.AppId()) {
+			t.Fatal("Manifest file was not mapped to its shortcut -", file)
+		}
+	}
+
+	for _, name := range names {
+		err := os.Remove(path.Join(gridDirPath, name))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	renamed := original
+	renamed.AppName = "Chess Deluxe"
+
+	writeTestShortcuts(t, dv, []shortcuts.Shortcut{renamed})
+
+	result, err := Restore(RestoreConfig{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		Reader:       archive,
+		Remap:        true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(result.Restored) != len(names) {
+		t.Fatal("Unexpected number of restored files -", len(result.Restored))
+	}
+
+	expected := map[string]string{
+		original.AppId() + "_hero.png": renamed.AppId() + "_hero.png",
+		original.AppId() + ".json":     renamed.AppId() + ".json",
+	}
+
+	if len(result.Remapped) != len(expected) {
+		t.Fatal("Unexpected remapped files -", result.Remapped)
+	}
+
+	for from, to := range expected {
+		if result.Remapped[from] != to {
+			t.Fatal("Expected '" + from + "' to be remapped to '" + to + "' - got '" + result.Remapped[from] + "'")
+		}
+
+		data, err := ioutil.ReadFile(path.Join(gridDirPath, to))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if string(data) != from {
+			t.Fatal("Unexpected restored file contents - '" + string(data) + "'")
+		}
+	}
+
+	_, err = os.Stat(path.Join(gridDirPath, "400p.png"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestRestoreCreatesGridDir(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	gridDirPath := locations.GridDirPath(dv.RootDirPath(), testUserId)
+
+	err := ioutil.WriteFile(path.Join(gridDirPath, "400p.png"), []byte("400p.png"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	archive := bytes.NewBuffer(nil)
+
+	_, err = Export(ExportConfig{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		Writer:       archive,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = os.RemoveAll(gridDirPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	result, err := Restore(RestoreConfig{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		Reader:       archive,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(result.Restored) != 1 {
+		t.Fatal("Unexpected restored files -", result.Restored)
+	}
+
+	_, err = os.Stat(path.Join(gridDirPath, "400p.png"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestRestoreRejectsPathNames(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	for _, name := range []string{`..\..\400.png`, "../400.png", "..", "notes.txt"} {
+		rawManifest, err := json.Marshal(Manifest{
+			Version:     currentManifestVersion,
+			OwnerUserId: testUserId,
+			Files: []ManifestFile{
+				{Name: name},
+			},
+		})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		archive := bytes.NewBuffer(nil)
+		zw := zip.NewWriter(archive)
+
+		for entryName, data := range map[string][]byte{
+			manifestFileName:                rawManifest,
+			archiveGridDirName + "/" + name: []byte("not really a png"),
+		} {
+			w, err := zw.Create(entryName)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			_, err = w.Write(data)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+
+		err = zw.Close()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		_, err = Restore(RestoreConfig{
+			DataVerifier: dv,
+			OwnerUserId:  testUserId,
+			Reader:       archive,
+		})
+		if err == nil {
+			t.Fatal("Restored an archive containing '" + name + "'")
+		}
+	}
+
+	infos, err := ioutil.ReadDir(dv.RootDirPath())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, info := range infos {
+		if strings.HasSuffix(info.Name(), ".png") {
+			t.Fatal("File was written outside of the grid directory - '" + info.Name() + "'")
+		}
+	}
+}
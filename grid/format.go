@@ -0,0 +1,125 @@
+package grid
+
+import (
+	"bytes"
+	_ "image/gif"
+	_ "image/jpeg"
+	"io"
+)
+
+const (
+	// Png is the PNG image format. Its files use the '.png'
+	// extension.
+	Png ImageFormat = "png"
+
+	// Jpeg is the JPEG image format. Its files use the '.jpg'
+	// extension.
+	Jpeg ImageFormat = "jpeg"
+
+	// WebP is the WebP image format. Its files use the '.webp'
+	// extension.
+	WebP ImageFormat = "webp"
+
+	// Gif is the GIF image format. Its files use the '.gif'
+	// extension.
+	Gif ImageFormat = "gif"
+)
+
+const (
+	// formatHeaderLength is the number of bytes needed to identify
+	// all of the supported image formats.
+	formatHeaderLength = 12
+)
+
+var (
+	pngMagic  = []byte("\x89PNG\r\n\x1a\n")
+	jpegMagic = []byte{0xff, 0xd8, 0xff}
+	gif87a    = []byte("GIF87a")
+	gif89a    = []byte("GIF89a")
+	riffMagic = []byte("RIFF")
+	webPMagic = []byte("WEBP")
+)
+
+// ImageFormat is the encoding of an image file.
+type ImageFormat string
+
+// Extension returns the file extension that Steam expects for images
+// encoded in the ImageFormat.
+func (o ImageFormat) Extension() string {
+	switch o {
+	case Png:
+		return ".png"
+	case Jpeg:
+		return ".jpg"
+	case WebP:
+		return ".webp"
+	case Gif:
+		return ".gif"
+	}
+
+	return ""
+}
+
+// UnsupportedFormatError is returned when an image's format cannot be
+// identified, or when an operation does not support the image's format.
+type UnsupportedFormatError struct {
+	// Format is the detected image format. It is empty if the format
+	// could not be identified.
+	Format ImageFormat
+
+	// Operation is the operation that does not support the format.
+	// It is empty if the format could not be identified.
+	Operation string
+}
+
+func (o *UnsupportedFormatError) Error() string {
+	if len(o.Format) == 0 {
+		return "the image format is not supported - expected a png, jpeg, webp, or gif image"
+	}
+
+	return "the " + string(o.Format) + " image format is not supported by the " +
+		o.Operation + " operation"
+}
+
+// DetectFormat identifies an image's format by inspecting its first few
+// bytes. An *UnsupportedFormatError is returned if the format is
+// not recognized.
+func DetectFormat(r io.Reader) (ImageFormat, error) {
+	header := make([]byte, formatHeaderLength)
+
+	n, err := io.ReadFull(r, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	return detectFormat(header[:n])
+}
+
+func detectFormat(header []byte) (ImageFormat, error) {
+	switch {
+	case bytes.HasPrefix(header, pngMagic):
+		return Png, nil
+	case bytes.HasPrefix(header, jpegMagic):
+		return Jpeg, nil
+	case bytes.HasPrefix(header, gif87a), bytes.HasPrefix(header, gif89a):
+		return Gif, nil
+	case len(header) >= 12 && bytes.Equal(header[0:4], riffMagic) && bytes.Equal(header[8:12], webPMagic):
+		return WebP, nil
+	}
+
+	return "", &UnsupportedFormatError{}
+}
+
+// canConvertToPng returns a non-nil error if images of the specified
+// format cannot be converted to png.
+func canConvertToPng(format ImageFormat) error {
+	switch format {
+	case Png, Jpeg, Gif:
+		return nil
+	}
+
+	return &UnsupportedFormatError{
+		Format:    format,
+		Operation: "png conversion",
+	}
+}
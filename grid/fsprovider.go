@@ -0,0 +1,261 @@
+package grid
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultMinMatchScore = 0.6
+)
+
+// FilesystemProviderConfig configures a filesystem artwork Provider.
+type FilesystemProviderConfig struct {
+	// RootDirPath is the path to the artwork directory. The directory
+	// contains one sub directory per game, named after the game's
+	// title (or its app ID). Each game directory contains images
+	// named after the artwork slot they are for, such as
+	// 'hero.png' or 'portrait.jpg'.
+	RootDirPath string
+
+	// MinScore is the minimum similarity score (between 0 and 1) that
+	// a game directory's name must have to be considered a match for
+	// a game's title. Defaults to defaultMinMatchScore.
+	MinScore float64
+}
+
+// Validate returns a non-nil error if the FilesystemProviderConfig
+// is invalid.
+func (o *FilesystemProviderConfig) Validate() error {
+	if len(strings.TrimSpace(o.RootDirPath)) == 0 {
+		return errors.New("please specify an artwork directory path")
+	}
+
+	if o.MinScore < 0 || o.MinScore > 1 {
+		return errors.New("the minimum match score must be between 0 and 1")
+	}
+
+	if o.MinScore == 0 {
+		o.MinScore = defaultMinMatchScore
+	}
+
+	return nil
+}
+
+type filesystemProvider struct {
+	config FilesystemProviderConfig
+}
+
+func (o *filesystemProvider) Lookup(query Query) ([]Candidate, error) {
+	infos, err := ioutil.ReadDir(o.config.RootDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var bestName string
+	var bestScore float64
+
+	exeName := executableName(query.ExePath)
+
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+
+		var score float64
+
+		switch {
+		case len(query.AppId) > 0 && info.Name() == query.AppId:
+			score = 1
+		case len(query.Title) > 0:
+			score = matchScore(query.Title, info.Name())
+		}
+
+		if len(exeName) > 0 && score < 1 {
+			exeScore := matchScore(exeName, info.Name())
+			if exeScore > score {
+				score = exeScore
+			}
+		}
+
+		if score >= o.config.MinScore && score > bestScore {
+			bestName = info.Name()
+			bestScore = score
+		}
+	}
+
+	if len(bestName) == 0 {
+		return nil, nil
+	}
+
+	return o.candidates(path.Join(o.config.RootDirPath, bestName))
+}
+
+func (o *filesystemProvider) candidates(gameDirPath string) ([]Candidate, error) {
+	infos, err := ioutil.ReadDir(gameDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Candidate
+
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		slot, ok := slotFromArtworkName(info.Name())
+		if !ok {
+			continue
+		}
+
+		filePath := path.Join(gameDirPath, info.Name())
+
+		candidates = append(candidates, Candidate{
+			Slot:   slot,
+			Source: filePath,
+			Open: func() (io.ReadCloser, error) {
+				return os.Open(filePath)
+			},
+		})
+	}
+
+	// Prefer images named exactly after their slot (e.g., 'hero.png'
+	// over 'hero-alt.png').
+	sort.SliceStable(candidates, func(i, j int) bool {
+		iBase := artworkBaseName(candidates[i].Source)
+		jBase := artworkBaseName(candidates[j].Source)
+
+		if len(iBase) != len(jBase) {
+			return len(iBase) < len(jBase)
+		}
+
+		return iBase < jBase
+	})
+
+	return candidates, nil
+}
+
+func artworkBaseName(filePath string) string {
+	name := path.Base(filePath)
+
+	return strings.ToLower(strings.TrimSuffix(name, path.Ext(name)))
+}
+
+// slotFromArtworkName returns the Slot for an artwork file name, such as
+// 'hero.png' or 'portrait-alt.jpg'.
+func slotFromArtworkName(name string) (Slot, bool) {
+	base := artworkBaseName(name)
+
+	for _, slot := range Slots() {
+		slotName := slot.String()
+
+		if base == slotName {
+			return slot, true
+		}
+
+		if strings.HasPrefix(base, slotName) {
+			next := rune(base[len(slotName)])
+			if !unicode.IsLetter(next) && !unicode.IsDigit(next) {
+				return slot, true
+			}
+		}
+	}
+
+	return WideSlot, false
+}
+
+// executableName returns an executable's file name without its extension
+// or any surrounding quotation marks.
+func executableName(exePath string) string {
+	exePath = strings.Trim(exePath, `"`)
+	if len(exePath) == 0 {
+		return ""
+	}
+
+	base := path.Base(strings.Replace(exePath, `\`, "/", -1))
+
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+// matchScore returns the similarity of two names between 0 and 1,
+// ignoring case, punctuation, and whitespace.
+func matchScore(a string, b string) float64 {
+	a = normalizeName(a)
+	b = normalizeName(b)
+
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	if a == b {
+		return 1
+	}
+
+	aBigrams := bigrams(a)
+	bBigrams := bigrams(b)
+
+	if len(aBigrams) == 0 || len(bBigrams) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int)
+
+	for _, bigram := range aBigrams {
+		counts[bigram]++
+	}
+
+	matches := 0
+
+	for _, bigram := range bBigrams {
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			matches++
+		}
+	}
+
+	return float64(2*matches) / float64(len(aBigrams)+len(bBigrams))
+}
+
+func normalizeName(name string) string {
+	var sb strings.Builder
+
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func bigrams(s string) []string {
+	runes := []rune(s)
+
+	var result []string
+
+	for i := 0; i < len(runes)-1; i++ {
+		result = append(result, string(runes[i:i+2]))
+	}
+
+	return result
+}
+
+// NewFilesystemProvider creates a Provider that looks up artwork in
+// a local directory.
+func NewFilesystemProvider(config FilesystemProviderConfig) (Provider, error) {
+	err := config.Validate()
+	if err != nil {
+		return &filesystemProvider{}, err
+	}
+
+	return &filesystemProvider{
+		config: config,
+	}, nil
+}
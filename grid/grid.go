@@ -1,19 +1,26 @@
 package grid
 
 import (
+	"bytes"
 	"errors"
+	"image"
+	"image/png"
 	"io"
 	"io/ioutil"
 	"os"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/stephen-fox/steamutil/locations"
 	"github.com/stephen-fox/steamutil/naming"
+	"github.com/stephen-fox/steamutil/shortcuts"
 )
 
 const (
-	defaultImageMode = 0644
+	defaultImageMode   = 0644
+	defaultGridDirMode = 0755
 )
 
 // ImageDetails stores important details about the grid image, such as
@@ -25,12 +32,27 @@ type ImageDetails struct {
 	// OwnerUserId is the Steam user ID for the image being operated on.
 	OwnerUserId string
 
+	// AppId is the app ID of the game for which the grid image is for.
+	// This is typically the app ID of a game distributed by Steam.
+	// If set, Shortcut, GameName, and GameExecutablePath are ignored.
+	AppId string
+
+	// Shortcut is the non-Steam game shortcut for which the grid image
+	// is for. The image is named using the shortcut's app ID. If set,
+	// GameName and GameExecutablePath are ignored.
+	Shortcut *shortcuts.Shortcut
+
 	// GameName is the name of the game for which the grid image is for.
+	// The image is named using the game's legacy ID.
 	GameName string
 
 	// GameExecutablePath is the full executable path (including any
 	// quotation marks or other characters) for the grid image's game.
 	GameExecutablePath string
+
+	// Slot is the artwork slot that the image is displayed in.
+	// Defaults to WideSlot.
+	Slot Slot
 }
 
 // Validate returns a non-nil error if the ImageDetails is invalid.
@@ -39,8 +61,17 @@ func (o *ImageDetails) Validate() error {
 		return errors.New("the DataVerifier cannot be nil")
 	}
 
-	if len(strings.TrimSpace(o.OwnerUserId)) == 0 {
-		return errors.New("please specify a Steam user ID")
+	ownerUserId, err := parseOwnerUserId(o.OwnerUserId)
+	if err != nil {
+		return err
+	}
+	o.OwnerUserId = ownerUserId
+
+	if len(o.AppId) > 0 {
+		id, err := strconv.ParseUint(o.AppId, 10, 32)
+		if err != nil || id == 0 {
+			return errors.New("the app ID '" + o.AppId + "' is not a valid app ID")
+		}
 	}
 
 	return nil
@@ -61,9 +92,49 @@ func (o *ImageDetails) FilePath(optionalExtension string) (string, error) {
 		return "", err
 	}
 
-	gameId := naming.LegacyNonSteamGameId(o.GameName, o.GameExecutablePath)
+	return path.Join(gridDirPath, o.gameId()+o.Slot.Suffix()) + optionalExtension, nil
+}
+
+// parseOwnerUserId validates a Steam user ID in any of the formats that
+// are accepted by naming.ParseSteamID, and returns the user's account ID.
+func parseOwnerUserId(userId string) (string, error) {
+	if len(strings.TrimSpace(userId)) == 0 {
+		return "", errors.New("please specify a Steam user ID")
+	}
 
-	return path.Join(gridDirPath, gameId) + optionalExtension, nil
+	return naming.ParseUserId(userId)
+}
+
+// ensureGridDir creates the grid images directory of the specified user
+// if it does not exist. The user's data directory must already exist.
+func ensureGridDir(dv locations.DataVerifier, userId string) error {
+	_, _, err := dv.GridDirPath(userId)
+	if err == nil || !os.IsNotExist(err) {
+		return err
+	}
+
+	rootDirPath := dv.RootDirPath()
+	fs := locations.FileSystemOf(dv)
+
+	_, err = fs.Stat(locations.UserIdDirPath(rootDirPath, userId))
+	if err != nil {
+		return err
+	}
+
+	return fs.MkdirAll(locations.GridDirPath(rootDirPath, userId), defaultGridDirMode)
+}
+
+// gameId returns the game ID that the image's file name is keyed by.
+func (o *ImageDetails) gameId() string {
+	if len(o.AppId) > 0 {
+		return o.AppId
+	}
+
+	if o.Shortcut != nil {
+		return o.Shortcut.AppId()
+	}
+
+	return naming.LegacyNonSteamGameId(o.GameName, o.GameExecutablePath)
 }
 
 // AddConfig configures the grid image addition operation.
@@ -72,15 +143,44 @@ type AddConfig struct {
 	ResultDetails ImageDetails
 
 	// ImageSourcePath is the source path of the image being operated on.
+	// It is ignored if ImageSource is set.
 	ImageSourcePath string
 
+	// ImageSource is an optional reader that provides the image being
+	// operated on. In-memory images can be provided by wrapping them
+	// with bytes.NewReader. The image's format is detected from
+	// its content.
+	ImageSource io.Reader
+
 	// OverwriteExisting specifies whether or not an existing grid image
-	// should be overwritten.
+	// should be overwritten. Existing images in the same slot are
+	// considered regardless of their extension. When overwriting, any
+	// such image with a different extension is removed so that Steam
+	// does not pick between them.
 	OverwriteExisting bool
 
 	// Mode specifies the os.FileMode for the resulting grid image file.
 	// If not specified, defaultImageMode will be used.
 	Mode os.FileMode
+
+	// LogoPosition optionally specifies the placement of the logo.
+	// It is only used when the ResultDetails Slot is LogoSlot.
+	LogoPosition *LogoPosition
+
+	// ConvertToPng specifies whether or not the image should be
+	// converted to a png before it is written. Only png, jpeg,
+	// and gif images can be converted.
+	ConvertToPng bool
+
+	// Fit specifies how the image is adjusted when its aspect ratio
+	// does not match the recommended size for the ResultDetails Slot.
+	// Adjusted images are always written as a png. Defaults to NoFit.
+	Fit FitMode
+
+	// OnSizeMismatch is an optional function that is called when the
+	// image's aspect ratio does not match the recommended size for
+	// the ResultDetails Slot.
+	OnSizeMismatch func(slot Slot, actual image.Point, recommended image.Point)
 }
 
 // Validate returns a non-nil error if the AddConfig is invalid.
@@ -90,14 +190,21 @@ func (o *AddConfig) Validate() error {
 		return err
 	}
 
-	if len(strings.TrimSpace(o.ImageSourcePath)) == 0 {
-		return errors.New("please specify a tile image source path")
+	if o.ImageSource == nil && len(strings.TrimSpace(o.ImageSourcePath)) == 0 {
+		return errors.New("please specify a tile image source path or reader")
 	}
 
 	if o.Mode == 0 {
 		o.Mode = defaultImageMode
 	}
 
+	if o.LogoPosition != nil && o.ResultDetails.Slot == LogoSlot {
+		err := o.LogoPosition.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -106,10 +213,24 @@ type RemoveConfig struct {
 	// TargetDetails specifies details about the image to be removed.
 	TargetDetails ImageDetails
 
-	// FileExtension is the file extension to target. If not set,
-	// the remove operation will target all files matching the
-	// specified TargetDetails.
+	// FileExtension is the file extension to target. If set, only
+	// the file in the TargetDetails Slot with that extension is
+	// targeted. If not set, the remove operation will target files
+	// with any extension in every artwork slot, including the logo
+	// position file, unless SingleSlot is set.
+	//
+	// If the TargetDetails Slot is LogoSlot, the logo position
+	// file is removed as well.
 	FileExtension string
+
+	// SingleSlot specifies whether or not only files in the
+	// TargetDetails Slot should be targeted when FileExtension
+	// is not set.
+	SingleSlot bool
+
+	// DryRun specifies whether or not the remove operation should
+	// only report which files would be removed.
+	DryRun bool
 }
 
 // Validate returns a non-nil error if the RemoveConfig is invalid.
@@ -122,90 +243,285 @@ func (o *RemoveConfig) Validate() error {
 	return nil
 }
 
-// AddImage adds an image as a Steam grid image.
+// AddImage adds an image as a Steam grid image. The image is written to
+// a temporary file in the grid directory and then renamed, so Steam never
+// sees a partially written image. The user's grid directory is created
+// if it does not exist.
 func AddImage(config AddConfig) error {
+	_, err := addImage(config)
+	return err
+}
+
+// addImage adds an image as a Steam grid image, returning the path of
+// the resulting file. An empty path is returned if an image already
+// exists in the slot and OverwriteExisting is false.
+func addImage(config AddConfig) (string, error) {
 	err := config.Validate()
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	gridDirPath, _, err := config.ResultDetails.DataVerifier.GridDirPath(config.ResultDetails.OwnerUserId)
+	err = ensureGridDir(config.ResultDetails.DataVerifier, config.ResultDetails.OwnerUserId)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	gameId := naming.LegacyNonSteamGameId(config.ResultDetails.GameName, config.ResultDetails.GameExecutablePath)
+	var raw []byte
 
-	resultingFilePath := path.Join(gridDirPath, gameId)
+	if config.ImageSource != nil {
+		raw, err = ioutil.ReadAll(config.ImageSource)
+	} else {
+		raw, err = ioutil.ReadFile(config.ImageSourcePath)
+	}
+	if err != nil {
+		return "", err
+	}
 
-	extensionIndex := strings.LastIndex(config.ImageSourcePath, ".")
-	if extensionIndex > 0 {
-		resultingFilePath = resultingFilePath + config.ImageSourcePath[extensionIndex:]
+	raw, format, err := prepareImage(config, raw)
+	if err != nil {
+		return "", err
 	}
 
-	if !config.OverwriteExisting {
-		_, statErr := os.Stat(resultingFilePath)
-		if statErr == nil {
-			return nil
-		}
+	resultingFilePath, err := config.ResultDetails.FilePath(format.Extension())
+	if err != nil {
+		return "", err
 	}
 
-	source, err := os.Open(config.ImageSourcePath)
+	existing, err := slotImagePaths(config.ResultDetails)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	if len(existing) > 0 && !config.OverwriteExisting {
+		return "", nil
 	}
-	defer source.Close()
 
-	dest, err := os.OpenFile(resultingFilePath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, config.Mode)
+	fs := locations.FileSystemOf(config.ResultDetails.DataVerifier)
+
+	err = fs.WriteFile(resultingFilePath, raw, config.Mode)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer dest.Close()
 
-	_, err = io.Copy(dest, source)
+	for _, p := range existing {
+		if p == resultingFilePath {
+			continue
+		}
+
+		err := fs.Remove(p)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
+	if config.LogoPosition != nil && config.ResultDetails.Slot == LogoSlot {
+		err := SetLogoPosition(SetLogoPositionConfig{
+			TargetDetails: config.ResultDetails,
+			Position:      *config.LogoPosition,
+			Mode:          config.Mode,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return resultingFilePath, nil
+}
+
+// slotImagePaths returns the paths of the images, with any extension,
+// in the grid directory for the game and slot in the ImageDetails.
+func slotImagePaths(details ImageDetails) ([]string, error) {
+	gridDirPath, _, err := details.DataVerifier.GridDirPath(details.OwnerUserId)
 	if err != nil {
-		return err
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
 	}
 
-	return nil
+	infos, err := locations.FileSystemOf(details.DataVerifier).ReadDir(gridDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	gameId := details.gameId()
+
+	var paths []string
+
+	for _, info := range infos {
+		entry, ok := ParseFileName(info.Name())
+		if ok && !entry.IsLogoPosition && entry.Id == gameId && entry.Slot == details.Slot {
+			paths = append(paths, path.Join(gridDirPath, info.Name()))
+		}
+	}
+
+	return paths, nil
 }
 
-// RemoveImage removes a Steam grid image.
-func RemoveImage(config RemoveConfig) error {
-	err := config.Validate()
+// prepareImage applies the format conversion and resizing options in
+// the AddConfig to an encoded image. It returns the resulting image
+// and its format.
+func prepareImage(config AddConfig, raw []byte) ([]byte, ImageFormat, error) {
+	format, err := detectFormat(raw)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	filePath, err := config.TargetDetails.FilePath(config.FileExtension)
+	slot := config.ResultDetails.Slot
+	fit := false
+
+	if config.OnSizeMismatch != nil || config.Fit != NoFit {
+		size, err := ImageSize(raw)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if !slot.MatchesRecommendedSize(size) {
+			if config.OnSizeMismatch != nil {
+				config.OnSizeMismatch(slot, size, slot.RecommendedSize())
+			}
+
+			fit = config.Fit != NoFit
+		}
+	}
+
+	if !fit && (!config.ConvertToPng || format == Png) {
+		return raw, format, nil
+	}
+
+	err = canConvertToPng(format)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	if len(config.FileExtension) != 0 {
-		os.Remove(filePath)
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, "", err
+	}
 
-		return nil
+	if fit {
+		img = fitImage(img, slot.RecommendedSize(), config.Fit)
 	}
 
-	dirPath := path.Dir(filePath)
+	buffer := bytes.NewBuffer(nil)
 
-	infos, err := ioutil.ReadDir(dirPath)
+	err = png.Encode(buffer, img)
 	if err != nil {
-		return err
+		return nil, "", err
+	}
+
+	return buffer.Bytes(), Png, nil
+}
+
+// RemoveError is returned when one or more grid files could not
+// be removed (or, by CleanupOrphans, archived).
+type RemoveError struct {
+	// Failures maps the paths of the files that could not be removed
+	// to the error that occurred.
+	Failures map[string]error
+}
+
+func (o *RemoveError) Error() string {
+	var paths []string
+
+	for p := range o.Failures {
+		paths = append(paths, p)
 	}
 
-	filenamePrefix := path.Base(filePath)
+	sort.Strings(paths)
+
+	var messages []string
+
+	for _, p := range paths {
+		messages = append(messages, "'"+p+"' - "+o.Failures[p].Error())
+	}
+
+	return "failed to remove " + strconv.Itoa(len(paths)) + " grid file(s): " +
+		strings.Join(messages, ", ")
+}
+
+// RemoveImage removes Steam grid images. Files are matched by their exact
+// game ID. If FileExtension or SingleSlot is set, files are also matched
+// by their artwork slot. The paths of the removed files are returned,
+// or the paths of the files that would be removed if DryRun is set.
+//
+// Every matching file is attempted. If any could not be removed,
+// a *RemoveError is returned along with the paths of the files
+// that were removed.
+func RemoveImage(config RemoveConfig) ([]string, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	gridDirPath, _, err := config.TargetDetails.DataVerifier.GridDirPath(config.TargetDetails.OwnerUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := locations.FileSystemOf(config.TargetDetails.DataVerifier)
+
+	infos, err := fs.ReadDir(gridDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	gameId := config.TargetDetails.gameId()
+	slot := config.TargetDetails.Slot
+	allSlots := len(config.FileExtension) == 0 && !config.SingleSlot
+
+	var targets []string
 
 	for _, info := range infos {
 		if info.IsDir() {
 			continue
 		}
 
-		if strings.HasPrefix(info.Name(), filenamePrefix) {
-			os.Remove(path.Join(dirPath, info.Name()))
+		entry, ok := ParseFileName(info.Name())
+		if !ok || entry.Id != gameId {
+			continue
 		}
+
+		if entry.IsLogoPosition {
+			if !allSlots && slot != LogoSlot {
+				continue
+			}
+		} else {
+			if !allSlots && entry.Slot != slot {
+				continue
+			}
+
+			if len(config.FileExtension) > 0 && !strings.EqualFold(entry.Extension, config.FileExtension) {
+				continue
+			}
+		}
+
+		targets = append(targets, path.Join(gridDirPath, info.Name()))
 	}
 
-	return nil
+	if config.DryRun {
+		return targets, nil
+	}
+
+	var removed []string
+	failures := make(map[string]error)
+
+	for _, target := range targets {
+		err := fs.Remove(target)
+		if err != nil {
+			failures[target] = err
+			continue
+		}
+
+		removed = append(removed, target)
+	}
+
+	if len(failures) > 0 {
+		return removed, &RemoveError{
+			Failures: failures,
+		}
+	}
+
+	return removed, nil
 }
@@ -0,0 +1,551 @@
+package grid
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+const (
+	testUserId = "12345678"
+)
+
+func TestAddImageMisnamedPng(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	sourcePath := writeTestImage(t, dv.RootDirPath(), "cover.jpeg", Png)
+
+	details := ImageDetails{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		GameName:     "Chess",
+	}
+
+	err := AddImage(AddConfig{
+		ResultDetails:   details,
+		ImageSourcePath: sourcePath,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expectedPath, err := details.FilePath(".png")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = os.Stat(expectedPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestAddImageNoExtension(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	sourceDirPath := path.Join(dv.RootDirPath(), "a.b")
+
+	err := os.Mkdir(sourceDirPath, 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	sourcePath := writeTestImage(t, sourceDirPath, "cover", Jpeg)
+
+	details := ImageDetails{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		GameName:     "Chess",
+		Slot:         PortraitSlot,
+	}
+
+	err = AddImage(AddConfig{
+		ResultDetails:   details,
+		ImageSourcePath: sourcePath,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expectedPath, err := details.FilePath(".jpg")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = os.Stat(expectedPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestAddImageUnsupportedFormat(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	sourcePath := path.Join(dv.RootDirPath(), "cover.png")
+
+	err := ioutil.WriteFile(sourcePath, []byte("definitely not an image"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = AddImage(AddConfig{
+		ResultDetails: ImageDetails{
+			DataVerifier: dv,
+			OwnerUserId:  testUserId,
+			GameName:     "Chess",
+		},
+		ImageSourcePath: sourcePath,
+	})
+	if err == nil {
+		t.Fatal("Adding an unsupported image did not produce an error")
+	}
+
+	_, ok := err.(*UnsupportedFormatError)
+	if !ok {
+		t.Fatal("Unexpected error type -", err.Error())
+	}
+}
+
+func TestAddImageConvertToPng(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	sourcePath := writeTestImage(t, dv.RootDirPath(), "cover.jpg", Jpeg)
+
+	details := ImageDetails{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		GameName:     "Chess",
+		Slot:         HeroSlot,
+	}
+
+	err := AddImage(AddConfig{
+		ResultDetails:   details,
+		ImageSourcePath: sourcePath,
+		ConvertToPng:    true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	resultPath, err := details.FilePath(".png")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	f, err := os.Open(resultPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer f.Close()
+
+	format, err := DetectFormat(f)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if format != Png {
+		t.Fatal("Resulting image is not a png - got", format)
+	}
+}
+
+func TestAddImageFromReader(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	sourcePath := writeTestImage(t, dv.RootDirPath(), "cover", Png)
+
+	raw, err := ioutil.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	details := ImageDetails{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		GameName:     "Chess",
+		Slot:         IconSlot,
+	}
+
+	err = AddImage(AddConfig{
+		ResultDetails: details,
+		ImageSource:   bytes.NewReader(raw),
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	resultPath, err := details.FilePath(".png")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	result, err := ioutil.ReadFile(resultPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !bytes.Equal(result, raw) {
+		t.Fatal("Resulting image does not match the source image")
+	}
+
+	infos, err := ioutil.ReadDir(path.Dir(resultPath))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(infos) != 1 {
+		t.Fatal("Expected only the resulting image in the grid directory - got", len(infos), "files")
+	}
+}
+
+func TestAddImageOtherExtension(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	details := ImageDetails{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		GameName:     "Chess",
+	}
+
+	sourcePath := writeTestImage(t, dv.RootDirPath(), "cover.jpg", Jpeg)
+
+	pngPath, err := details.FilePath(".png")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = ioutil.WriteFile(pngPath, []byte("existing"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	jpgPath, err := details.FilePath(".jpg")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = AddImage(AddConfig{
+		ResultDetails:   details,
+		ImageSourcePath: sourcePath,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = os.Stat(jpgPath)
+	if !os.IsNotExist(err) {
+		t.Fatal("Image was added to a slot that already has an image")
+	}
+
+	err = AddImage(AddConfig{
+		ResultDetails:     details,
+		ImageSourcePath:   sourcePath,
+		OverwriteExisting: true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = os.Stat(jpgPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = os.Stat(pngPath)
+	if !os.IsNotExist(err) {
+		t.Fatal("Existing image with a different extension was not removed")
+	}
+}
+
+func TestAddImageFileSystem(t *testing.T) {
+	dirPath := t.TempDir()
+
+	writable, err := locations.NewFSDataVerifier(locations.DirFS(dirPath))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = locations.FileSystemOf(writable).MkdirAll(locations.UserIdDirPath(writable.RootDirPath(), testUserId), 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	sourcePath := writeTestImage(t, dirPath, "cover.png", Png)
+
+	config := AddConfig{
+		ResultDetails: ImageDetails{
+			DataVerifier: writable,
+			OwnerUserId:  testUserId,
+			AppId:        "400",
+		},
+		ImageSourcePath: sourcePath,
+	}
+
+	err = AddImage(config)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = os.Stat(path.Join(locations.GridDirPath(dirPath, testUserId), "400.png"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	readOnly, err := locations.NewFSDataVerifier(os.DirFS(dirPath))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	config.ResultDetails.DataVerifier = readOnly
+	config.ResultDetails.Slot = HeroSlot
+
+	err = AddImage(config)
+	if err != locations.ErrReadOnly {
+		t.Fatal("Expected a read-only error - got", err)
+	}
+}
+
+func TestRemoveImageExactMatch(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	details := ImageDetails{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		GameName:     "Chess",
+	}
+
+	gameId := details.gameId()
+	gridDirPath := locations.GridDirPath(dv.RootDirPath(), testUserId)
+
+	names := []string{
+		gameId + ".png",
+		gameId + ".jpg",
+		gameId + "p.png",
+		gameId + "1.png",
+		gameId + ".json",
+	}
+
+	for _, name := range names {
+		err := ioutil.WriteFile(path.Join(gridDirPath, name), []byte(name), 0600)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	dryRun, err := RemoveImage(RemoveConfig{
+		TargetDetails: details,
+		SingleSlot:    true,
+		DryRun:        true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(dryRun) != 2 {
+		t.Fatal("Unexpected dry run result -", dryRun)
+	}
+
+	for _, name := range names {
+		_, err := os.Stat(path.Join(gridDirPath, name))
+		if err != nil {
+			t.Fatal("Dry run removed a file - " + err.Error())
+		}
+	}
+
+	removed, err := RemoveImage(RemoveConfig{
+		TargetDetails: details,
+		FileExtension: ".png",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(removed) != 1 || path.Base(removed[0]) != gameId+".png" {
+		t.Fatal("Unexpected removed files -", removed)
+	}
+
+	removed, err = RemoveImage(RemoveConfig{
+		TargetDetails: details,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(removed) != 3 {
+		t.Fatal("Unexpected removed files -", removed)
+	}
+
+	_, err = os.Stat(path.Join(gridDirPath, gameId+"1.png"))
+	if err != nil {
+		t.Fatal("File for a different ID was removed - " + err.Error())
+	}
+}
+
+func TestImageDetails_FilePathIds(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	s := shortcuts.Shortcut{
+		AppName: "Pikmin",
+		ExePath: `D:\Program Files\Dolphin\Dolphin.exe`,
+	}
+
+	tests := map[string]ImageDetails{
+		"400_hero.png": {
+			AppId: "400",
+			Slot:  HeroSlot,
+		},
+		"2624352236p.png": {
+			Shortcut: &s,
+			Slot:     PortraitSlot,
+		},
+		"11271507026838028288.png": {
+			GameName:           s.AppName,
+			GameExecutablePath: `"` + s.ExePath + `"`,
+		},
+	}
+
+	for expected, details := range tests {
+		details.DataVerifier = dv
+		details.OwnerUserId = testUserId
+
+		filePath, err := details.FilePath(".png")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if path.Base(filePath) != expected {
+			t.Fatal("Expected '" + expected + "' - got '" + path.Base(filePath) + "'")
+		}
+	}
+
+	invalid := ImageDetails{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		AppId:        "not-a-number",
+	}
+
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatal("Invalid app ID did not produce an error")
+	}
+}
+
+func TestImageDetails_OwnerUserIdFormats(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	for _, userId := range []string{"[U:1:" + testUserId + "]", "76561197972611406", "STEAM_0:0:6172839"} {
+		details := ImageDetails{
+			DataVerifier: dv,
+			OwnerUserId:  userId,
+			AppId:        "400",
+		}
+
+		p, err := details.FilePath(".png")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if p != path.Join(locations.GridDirPath(dv.RootDirPath(), testUserId), "400.png") {
+			t.Fatal("Unexpected file path for '" + userId + "' - " + p)
+		}
+	}
+
+	details := ImageDetails{
+		DataVerifier: dv,
+		OwnerUserId:  "anonymous",
+		AppId:        "400",
+	}
+
+	err := details.Validate()
+	if err == nil {
+		t.Fatal("Expected an error for a malformed user ID")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	headers := map[ImageFormat][]byte{
+		Png:  []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0d"),
+		Jpeg: {0xff, 0xd8, 0xff, 0xe0},
+		Gif:  []byte("GIF89a"),
+		WebP: []byte("RIFF\x24\x00\x00\x00WEBPVP8 "),
+	}
+
+	for expected, header := range headers {
+		format, err := DetectFormat(bytes.NewReader(header))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if format != expected {
+			t.Fatal("Expected", expected, "- got", format)
+		}
+	}
+}
+
+func newTestDataVerifier(t *testing.T) locations.DataVerifier {
+	dataDir, err := ioutil.TempDir("", "steamutil-grid-test")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = os.MkdirAll(locations.GridDirPath(dataDir, testUserId), 0700)
+	if err != nil {
+		os.RemoveAll(dataDir)
+		t.Fatal(err.Error())
+	}
+
+	dv, err := locations.NewDataVerifierForDir(dataDir)
+	if err != nil {
+		os.RemoveAll(dataDir)
+		t.Fatal(err.Error())
+	}
+
+	return dv
+}
+
+func writeTestImage(t *testing.T, dirPath string, name string, format ImageFormat) string {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	buffer := bytes.NewBuffer(nil)
+
+	var err error
+
+	switch format {
+	case Png:
+		err = png.Encode(buffer, img)
+	case Jpeg:
+		err = jpeg.Encode(buffer, img, nil)
+	default:
+		t.Fatal("Cannot create test image with format", format)
+	}
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	filePath := path.Join(dirPath, name)
+
+	err = ioutil.WriteFile(filePath, buffer.Bytes(), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return filePath
+}
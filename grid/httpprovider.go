@@ -0,0 +1,242 @@
+package grid
+
+import (
+	"encoding/json"
+	"errors"
+	"image"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHttpTimeout = 30 * time.Second
+)
+
+var (
+	errNotFound = errors.New("the requested resource was not found")
+)
+
+// HttpProviderConfig configures an HTTP artwork Provider that speaks the
+// SteamGridDB v2 API.
+type HttpProviderConfig struct {
+	// BaseUrl is the base URL of the API, such as
+	// 'https://www.steamgriddb.com/api/v2'.
+	BaseUrl string
+
+	// ApiKey is an optional API key that is sent as a bearer token.
+	ApiKey string
+
+	// Client is an optional HTTP client. If not specified, a client
+	// with a timeout of defaultHttpTimeout is used.
+	Client *http.Client
+}
+
+// Validate returns a non-nil error if the HttpProviderConfig is invalid.
+func (o *HttpProviderConfig) Validate() error {
+	if len(strings.TrimSpace(o.BaseUrl)) == 0 {
+		return errors.New("please specify a base URL")
+	}
+
+	_, err := url.Parse(o.BaseUrl)
+	if err != nil {
+		return errors.New("failed to parse base URL - " + err.Error())
+	}
+
+	o.BaseUrl = strings.TrimSuffix(o.BaseUrl, "/")
+
+	if o.Client == nil {
+		o.Client = &http.Client{
+			Timeout: defaultHttpTimeout,
+		}
+	}
+
+	return nil
+}
+
+type httpProvider struct {
+	config HttpProviderConfig
+}
+
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Errors  []string        `json:"errors"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type apiGame struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type apiImage struct {
+	Id     int    `json:"id"`
+	Url    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+func (o *httpProvider) Lookup(query Query) ([]Candidate, error) {
+	var gamePath string
+
+	id, err := strconv.ParseUint(query.AppId, 10, 64)
+	if err == nil && classifyId(id) == SteamAppId {
+		gamePath = "steam/" + query.AppId
+	} else {
+		gameId, found, err := o.search(query.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			return nil, nil
+		}
+
+		gamePath = "game/" + strconv.Itoa(gameId)
+	}
+
+	var candidates []Candidate
+
+	for _, endpoint := range []string{"grids", "heroes", "logos", "icons"} {
+		var images []apiImage
+
+		err := o.get(endpoint+"/"+gamePath, &images)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, img := range images {
+			candidates = append(candidates, o.candidate(endpoint, img))
+		}
+	}
+
+	return candidates, nil
+}
+
+func (o *httpProvider) search(title string) (int, bool, error) {
+	if len(strings.TrimSpace(title)) == 0 {
+		return 0, false, nil
+	}
+
+	var games []apiGame
+
+	err := o.get("search/autocomplete/"+url.PathEscape(title), &games)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if len(games) == 0 {
+		return 0, false, nil
+	}
+
+	return games[0].Id, true, nil
+}
+
+func (o *httpProvider) candidate(endpoint string, img apiImage) Candidate {
+	var slot Slot
+
+	switch endpoint {
+	case "grids":
+		if img.Height > img.Width {
+			slot = PortraitSlot
+		} else {
+			slot = WideSlot
+		}
+	case "heroes":
+		slot = HeroSlot
+	case "logos":
+		slot = LogoSlot
+	case "icons":
+		slot = IconSlot
+	}
+
+	imageUrl := img.Url
+
+	return Candidate{
+		Slot:   slot,
+		Source: imageUrl,
+		Size:   image.Pt(img.Width, img.Height),
+		Open: func() (io.ReadCloser, error) {
+			return o.open(imageUrl, false)
+		},
+	}
+}
+
+// get requests an API endpoint and decodes the response's data into
+// the specified value. The value is left unchanged if the API
+// reports that the resource does not exist.
+func (o *httpProvider) get(endpoint string, data interface{}) error {
+	body, err := o.open(o.config.BaseUrl+"/"+endpoint, true)
+	if err == errNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	raw, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	var response apiResponse
+
+	err = json.Unmarshal(raw, &response)
+	if err != nil {
+		return errors.New("failed to parse response from '" + endpoint + "' - " + err.Error())
+	}
+
+	if !response.Success {
+		return errors.New("request to '" + endpoint + "' failed - " + strings.Join(response.Errors, ", "))
+	}
+
+	return json.Unmarshal(response.Data, data)
+}
+
+// open performs a GET request. The API key is only sent when
+// authenticate is true so that it is not leaked to image hosts.
+func (o *httpProvider) open(rawUrl string, authenticate bool) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if authenticate && len(o.config.ApiKey) > 0 {
+		req.Header.Set("Authorization", "Bearer "+o.config.ApiKey)
+	}
+
+	resp, err := o.config.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, errNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.New("request to '" + rawUrl + "' failed - " + resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// NewHttpProvider creates a Provider that looks up artwork using an HTTP
+// API that is compatible with SteamGridDB's v2 API.
+func NewHttpProvider(config HttpProviderConfig) (Provider, error) {
+	err := config.Validate()
+	if err != nil {
+		return &httpProvider{}, err
+	}
+
+	return &httpProvider{
+		config: config,
+	}, nil
+}
@@ -0,0 +1,362 @@
+package grid
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+const (
+	// UnknownIdKind means the ID could not be classified.
+	UnknownIdKind IdKind = iota
+
+	// LegacyGameId is a 64-bit non-Steam game ID, as generated by
+	// naming.LegacyNonSteamGameId.
+	LegacyGameId
+
+	// ShortcutAppId is a 32-bit non-Steam game app ID, as generated by
+	// naming.NonSteamAppId.
+	ShortcutAppId
+
+	// SteamAppId is the app ID of a game distributed by Steam.
+	SteamAppId
+)
+
+const (
+	nonSteamAppIdBit  = 0x80000000
+	legacyGameIdLower = 0x02000000
+)
+
+// IdKind describes what kind of game ID a grid file name is keyed by.
+type IdKind int
+
+// String returns a human-readable name for the IdKind.
+func (o IdKind) String() string {
+	switch o {
+	case LegacyGameId:
+		return "legacy"
+	case ShortcutAppId:
+		return "appid"
+	case SteamAppId:
+		return "steam app"
+	}
+
+	return "unknown"
+}
+
+// Entry is a single file in a grid directory.
+type Entry struct {
+	// Name is the file's name.
+	Name string
+
+	// Path is the file's path.
+	Path string
+
+	// Id is the game ID portion of the file name.
+	Id string
+
+	// IdKind is the kind of game ID.
+	IdKind IdKind
+
+	// Slot is the artwork slot that the file is displayed in.
+	// It is meaningless if IsLogoPosition is true.
+	Slot Slot
+
+	// Extension is the file's extension, including the leading dot.
+	Extension string
+
+	// IsLogoPosition is true if the file is a logo position file.
+	IsLogoPosition bool
+
+	// Shortcut is the shortcut that the file belongs to. It is nil
+	// if the file does not belong to a shortcut.
+	Shortcut *shortcuts.Shortcut
+}
+
+// ParseFileName parses a grid directory file name into an Entry.
+// The Path and Shortcut fields are not set. False is returned if the
+// file name does not follow Steam's naming conventions.
+func ParseFileName(name string) (Entry, bool) {
+	entry := Entry{
+		Name:      name,
+		Extension: path.Ext(name),
+	}
+
+	if len(entry.Extension) <= 1 {
+		return Entry{}, false
+	}
+
+	base := strings.TrimSuffix(name, entry.Extension)
+
+	if entry.Extension == logoPositionExtension {
+		entry.IsLogoPosition = true
+	} else {
+		for _, slot := range Slots() {
+			suffix := slot.Suffix()
+			if len(suffix) > 0 && strings.HasSuffix(base, suffix) {
+				entry.Slot = slot
+				base = strings.TrimSuffix(base, suffix)
+				break
+			}
+		}
+	}
+
+	id, err := strconv.ParseUint(base, 10, 64)
+	if err != nil {
+		return Entry{}, false
+	}
+
+	entry.Id = base
+	entry.IdKind = classifyId(id)
+
+	if entry.IdKind == UnknownIdKind {
+		return Entry{}, false
+	}
+
+	return entry, true
+}
+
+func classifyId(id uint64) IdKind {
+	switch {
+	case id > 0xffffffff:
+		if id&0xffffffff == legacyGameIdLower && (id>>32)&nonSteamAppIdBit != 0 {
+			return LegacyGameId
+		}
+	case id&nonSteamAppIdBit != 0:
+		return ShortcutAppId
+	case id > 0:
+		return SteamAppId
+	}
+
+	return UnknownIdKind
+}
+
+// InventoryConfig configures the grid inventory operation.
+type InventoryConfig struct {
+	// DataVerifier is used to get the grid images directory and
+	// shortcuts file paths.
+	DataVerifier locations.DataVerifier
+
+	// OwnerUserId is the Steam user ID whose grid directory
+	// is inventoried.
+	OwnerUserId string
+
+	// InstalledAppIds is an optional list of the Steam app IDs that
+	// are installed. If nil, images for Steam apps are never
+	// considered orphans.
+	InstalledAppIds []string
+}
+
+// Validate returns a non-nil error if the InventoryConfig is invalid.
+func (o *InventoryConfig) Validate() error {
+	if o.DataVerifier == nil {
+		return errors.New("the DataVerifier cannot be nil")
+	}
+
+	ownerUserId, err := parseOwnerUserId(o.OwnerUserId)
+	if err != nil {
+		return err
+	}
+	o.OwnerUserId = ownerUserId
+
+	return nil
+}
+
+// Inventory describes the contents of a user's grid directory.
+type Inventory struct {
+	// Entries are the files that follow Steam's naming conventions.
+	Entries []Entry
+
+	// Orphans are the Entries whose game ID does not match any of the
+	// user's shortcuts or installed apps.
+	Orphans []Entry
+
+	// Unrecognized are the paths of files that do not follow Steam's
+	// naming conventions.
+	Unrecognized []string
+}
+
+// TakeInventory lists the files in a user's grid directory and
+// cross-references them with the user's shortcuts and installed apps.
+func TakeInventory(config InventoryConfig) (Inventory, error) {
+	err := config.Validate()
+	if err != nil {
+		return Inventory{}, err
+	}
+
+	gridDirPath, _, err := config.DataVerifier.GridDirPath(config.OwnerUserId)
+	if err != nil {
+		return Inventory{}, err
+	}
+
+	scs, err := readUserShortcuts(config.DataVerifier, config.OwnerUserId)
+	if err != nil {
+		return Inventory{}, err
+	}
+
+	idsToShortcuts := make(map[string]*shortcuts.Shortcut)
+
+	for i := range scs {
+		idsToShortcuts[scs[i].AppId()] = &scs[i]
+		idsToShortcuts[scs[i].LegacyId()] = &scs[i]
+	}
+
+	installed := make(map[string]bool)
+
+	for _, id := range config.InstalledAppIds {
+		installed[id] = true
+	}
+
+	infos, err := locations.FileSystemOf(config.DataVerifier).ReadDir(gridDirPath)
+	if err != nil {
+		return Inventory{}, err
+	}
+
+	var inventory Inventory
+
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		filePath := path.Join(gridDirPath, info.Name())
+
+		entry, ok := ParseFileName(info.Name())
+		if !ok {
+			inventory.Unrecognized = append(inventory.Unrecognized, filePath)
+			continue
+		}
+
+		entry.Path = filePath
+		entry.Shortcut = idsToShortcuts[entry.Id]
+
+		inventory.Entries = append(inventory.Entries, entry)
+
+		switch entry.IdKind {
+		case LegacyGameId, ShortcutAppId:
+			if entry.Shortcut == nil {
+				inventory.Orphans = append(inventory.Orphans, entry)
+			}
+		case SteamAppId:
+			if config.InstalledAppIds != nil && !installed[entry.Id] {
+				inventory.Orphans = append(inventory.Orphans, entry)
+			}
+		}
+	}
+
+	return inventory, nil
+}
+
+// readUserShortcuts reads the shortcuts for the specified user. No
+// shortcuts are returned if the user does not have a shortcuts file.
+func readUserShortcuts(dv locations.DataVerifier, userId string) ([]shortcuts.Shortcut, error) {
+	shortcutsPath, _, err := dv.ShortcutsFilePath(userId)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	f, err := locations.FileSystemOf(dv).Open(shortcutsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return shortcuts.Read(f)
+}
+
+// CleanupConfig configures the orphan cleanup operation.
+type CleanupConfig struct {
+	// DataVerifier is the DataVerifier that the orphans were found
+	// with. It is used to access the orphaned files. If not specified,
+	// the operating system's file system is used.
+	DataVerifier locations.DataVerifier
+
+	// Orphans are the entries to clean up. These are usually the
+	// Orphans field of an Inventory.
+	Orphans []Entry
+
+	// ArchiveDirPath is an optional directory to move the orphans
+	// into. If not specified, the orphans are removed. The directory
+	// is created if it does not exist, and is on the same file
+	// system as the orphans.
+	ArchiveDirPath string
+}
+
+// CleanupOrphans removes or archives orphaned grid files. It returns
+// the paths of the files that were cleaned up.
+//
+// Every orphan is attempted. If any could not be cleaned up, a
+// *RemoveError is returned along with the paths of the files that
+// were cleaned up.
+func CleanupOrphans(config CleanupConfig) ([]string, error) {
+	fs := locations.FileSystemOf(config.DataVerifier)
+
+	if len(config.ArchiveDirPath) > 0 {
+		err := fs.MkdirAll(config.ArchiveDirPath, 0755)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var cleaned []string
+	failures := make(map[string]error)
+
+	for _, orphan := range config.Orphans {
+		var err error
+
+		if len(config.ArchiveDirPath) > 0 {
+			err = moveFile(fs, orphan.Path, path.Join(config.ArchiveDirPath, orphan.Name))
+		} else {
+			err = fs.Remove(orphan.Path)
+		}
+		if err != nil {
+			failures[orphan.Path] = err
+			continue
+		}
+
+		cleaned = append(cleaned, orphan.Path)
+	}
+
+	if len(failures) > 0 {
+		return cleaned, &RemoveError{
+			Failures: failures,
+		}
+	}
+
+	return cleaned, nil
+}
+
+// moveFile renames a file, falling back to copying and removing it
+// when the destination is on a different device.
+func moveFile(fs locations.FileSystem, sourcePath string, destPath string) error {
+	err := fs.Rename(sourcePath, destPath)
+	if err == nil || !isCrossDeviceError(err) {
+		return err
+	}
+
+	info, err := fs.Stat(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := fs.ReadFile(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	err = fs.WriteFile(destPath, data, info.Mode())
+	if err != nil {
+		return err
+	}
+
+	return fs.Remove(sourcePath)
+}
@@ -0,0 +1,209 @@
+package grid
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+func TestParseFileName(t *testing.T) {
+	tests := map[string]Entry{
+		"2624352236p.png": {
+			Id:        "2624352236",
+			IdKind:    ShortcutAppId,
+			Slot:      PortraitSlot,
+			Extension: ".png",
+		},
+		"11271507026838028288.jpg": {
+			Id:        "11271507026838028288",
+			IdKind:    LegacyGameId,
+			Slot:      WideSlot,
+			Extension: ".jpg",
+		},
+		"400_hero.png": {
+			Id:        "400",
+			IdKind:    SteamAppId,
+			Slot:      HeroSlot,
+			Extension: ".png",
+		},
+		"2624352236.json": {
+			Id:             "2624352236",
+			IdKind:         ShortcutAppId,
+			Extension:      ".json",
+			IsLogoPosition: true,
+		},
+	}
+
+	for name, expected := range tests {
+		entry, ok := ParseFileName(name)
+		if !ok {
+			t.Fatal("Failed to parse '" + name + "'")
+		}
+
+		expected.Name = name
+
+		if entry != expected {
+			t.Fatal("Unexpected entry for '"+name+"' - got", entry)
+		}
+	}
+
+	for _, name := range []string{"notes.txt", "1234", "abcp.png", "0.png"} {
+		_, ok := ParseFileName(name)
+		if ok {
+			t.Fatal("Parsed invalid file name '" + name + "'")
+		}
+	}
+}
+
+func TestTakeInventory(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	s := shortcuts.Shortcut{
+		AppName: "Pikmin",
+		ExePath: `D:\Program Files\Dolphin\Dolphin.exe`,
+	}
+
+	writeTestShortcuts(t, dv, []shortcuts.Shortcut{s})
+
+	gridDirPath := locations.GridDirPath(dv.RootDirPath(), testUserId)
+
+	files := []string{
+		s.AppId() + "p.png",
+		s.LegacyId() + ".png",
+		s.AppId() + ".json",
+		"2147483649_hero.jpg",
+		"400_hero.png",
+		"500.png",
+		"notes.txt",
+	}
+
+	for _, name := range files {
+		err := ioutil.WriteFile(path.Join(gridDirPath, name), []byte(name), 0600)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	inventory, err := TakeInventory(InventoryConfig{
+		DataVerifier:    dv,
+		OwnerUserId:     testUserId,
+		InstalledAppIds: []string{"400"},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(inventory.Entries) != 6 {
+		t.Fatal("Unexpected number of entries -", len(inventory.Entries))
+	}
+
+	for _, entry := range inventory.Entries {
+		if entry.Id == s.AppId() || entry.Id == s.LegacyId() {
+			if entry.Shortcut == nil || entry.Shortcut.AppName != s.AppName {
+				t.Fatal("Entry was not matched to its shortcut - '" + entry.Name + "'")
+			}
+		}
+	}
+
+	if len(inventory.Unrecognized) != 1 || path.Base(inventory.Unrecognized[0]) != "notes.txt" {
+		t.Fatal("Unexpected unrecognized files -", inventory.Unrecognized)
+	}
+
+	var orphanNames []string
+
+	for _, orphan := range inventory.Orphans {
+		orphanNames = append(orphanNames, orphan.Name)
+	}
+
+	sort.Strings(orphanNames)
+
+	if len(orphanNames) != 2 || orphanNames[0] != "2147483649_hero.jpg" || orphanNames[1] != "500.png" {
+		t.Fatal("Unexpected orphans -", orphanNames)
+	}
+
+	archiveDirPath := path.Join(dv.RootDirPath(), "archive")
+
+	cleaned, err := CleanupOrphans(CleanupConfig{
+		Orphans:        inventory.Orphans,
+		ArchiveDirPath: archiveDirPath,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(cleaned) != 2 {
+		t.Fatal("Unexpected number of cleaned up files -", len(cleaned))
+	}
+
+	for _, name := range orphanNames {
+		_, err := os.Stat(path.Join(gridDirPath, name))
+		if err == nil {
+			t.Fatal("Orphan was not moved - '" + name + "'")
+		}
+
+		_, err = os.Stat(path.Join(archiveDirPath, name))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+}
+
+func TestCleanupOrphansContinuesAfterFailure(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	gridDirPath := locations.GridDirPath(dv.RootDirPath(), testUserId)
+
+	existingPath := path.Join(gridDirPath, "500.png")
+
+	err := ioutil.WriteFile(existingPath, []byte("x"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	missingPath := path.Join(gridDirPath, "400.png")
+
+	cleaned, err := CleanupOrphans(CleanupConfig{
+		Orphans: []Entry{
+			{Name: "400.png", Path: missingPath},
+			{Name: "500.png", Path: existingPath},
+		},
+	})
+
+	removeErr, ok := err.(*RemoveError)
+	if !ok {
+		t.Fatal("Expected a *RemoveError - got", err)
+	}
+
+	if len(removeErr.Failures) != 1 || removeErr.Failures[missingPath] == nil {
+		t.Fatal("Unexpected failures -", removeErr.Failures)
+	}
+
+	if len(cleaned) != 1 || cleaned[0] != existingPath {
+		t.Fatal("Unexpected cleaned up files -", cleaned)
+	}
+
+	_, err = os.Stat(existingPath)
+	if !os.IsNotExist(err) {
+		t.Fatal("Orphan was not removed")
+	}
+}
+
+func writeTestShortcuts(t *testing.T, dv locations.DataVerifier, scs []shortcuts.Shortcut) {
+	f, err := os.Create(locations.ShortcutsFilePath(dv.RootDirPath(), testUserId))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer f.Close()
+
+	err = shortcuts.WriteVdfV1(scs, f)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
@@ -0,0 +1,199 @@
+package grid
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+
+	"github.com/stephen-fox/steamutil/locations"
+)
+
+const (
+	// BottomLeft anchors the logo to the bottom left corner of the
+	// hero image.
+	BottomLeft PinnedPosition = "BottomLeft"
+
+	// UpperLeft anchors the logo to the upper left corner of the
+	// hero image.
+	UpperLeft PinnedPosition = "UpperLeft"
+
+	// CenterCenter anchors the logo to the center of the hero image.
+	CenterCenter PinnedPosition = "CenterCenter"
+
+	// UpperCenter anchors the logo to the center of the hero image's
+	// top edge.
+	UpperCenter PinnedPosition = "UpperCenter"
+
+	// BottomCenter anchors the logo to the center of the hero image's
+	// bottom edge.
+	BottomCenter PinnedPosition = "BottomCenter"
+)
+
+const (
+	logoPositionExtension = ".json"
+
+	currentLogoPositionVersion = 1
+)
+
+// PinnedPosition is the anchor point that Steam uses when placing
+// a logo on top of a hero image.
+type PinnedPosition string
+
+// IsValid returns true if the PinnedPosition is known to Steam.
+func (o PinnedPosition) IsValid() bool {
+	switch o {
+	case BottomLeft, UpperLeft, CenterCenter, UpperCenter, BottomCenter:
+		return true
+	}
+
+	return false
+}
+
+// LogoPosition describes the placement and size of a custom logo.
+type LogoPosition struct {
+	// PinnedPosition is the logo's anchor point.
+	PinnedPosition PinnedPosition `json:"pinnedPosition"`
+
+	// WidthPct is the width of the logo as a percentage of the
+	// hero image's width.
+	WidthPct float64 `json:"nWidthPct"`
+
+	// HeightPct is the height of the logo as a percentage of the
+	// hero image's height.
+	HeightPct float64 `json:"nHeightPct"`
+}
+
+// Validate returns a non-nil error if the LogoPosition is invalid.
+func (o LogoPosition) Validate() error {
+	if !o.PinnedPosition.IsValid() {
+		return errors.New("unknown logo pinned position '" + string(o.PinnedPosition) + "'")
+	}
+
+	if o.WidthPct <= 0 || o.WidthPct > 100 {
+		return errors.New("logo width percentage must be greater than 0 and at most 100 - got " +
+			strconv.FormatFloat(o.WidthPct, 'f', -1, 64))
+	}
+
+	if o.HeightPct <= 0 || o.HeightPct > 100 {
+		return errors.New("logo height percentage must be greater than 0 and at most 100 - got " +
+			strconv.FormatFloat(o.HeightPct, 'f', -1, 64))
+	}
+
+	return nil
+}
+
+// LogoPositionFile represents the '<game-id>.json' file that Steam writes
+// to the grid directory when a user positions a custom logo.
+type LogoPositionFile struct {
+	// Version is the file format version.
+	Version int `json:"nVersion"`
+
+	// Position is the logo's placement.
+	Position LogoPosition `json:"logoPosition"`
+}
+
+// SetLogoPositionConfig configures the logo position write operation.
+type SetLogoPositionConfig struct {
+	// TargetDetails specifies details about the logo being positioned.
+	// The Slot field is ignored.
+	TargetDetails ImageDetails
+
+	// Position is the logo's placement.
+	Position LogoPosition
+
+	// Mode specifies the os.FileMode for the resulting file.
+	// If not specified, defaultImageMode will be used.
+	Mode os.FileMode
+}
+
+// Validate returns a non-nil error if the SetLogoPositionConfig is invalid.
+func (o *SetLogoPositionConfig) Validate() error {
+	err := o.TargetDetails.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = o.Position.Validate()
+	if err != nil {
+		return err
+	}
+
+	if o.Mode == 0 {
+		o.Mode = defaultImageMode
+	}
+
+	return nil
+}
+
+// LogoPositionFilePath generates a file path for the logo position file.
+// Like FilePath, it does not test if the file exists.
+func (o *ImageDetails) LogoPositionFilePath() (string, error) {
+	logoDetails := *o
+	logoDetails.Slot = WideSlot
+
+	return logoDetails.FilePath(logoPositionExtension)
+}
+
+// ReadLogoPosition reads the logo position file for the specified game.
+func ReadLogoPosition(details ImageDetails) (LogoPositionFile, error) {
+	filePath, err := details.LogoPositionFilePath()
+	if err != nil {
+		return LogoPositionFile{}, err
+	}
+
+	raw, err := locations.FileSystemOf(details.DataVerifier).ReadFile(filePath)
+	if err != nil {
+		return LogoPositionFile{}, err
+	}
+
+	var file LogoPositionFile
+
+	err = json.Unmarshal(raw, &file)
+	if err != nil {
+		return LogoPositionFile{}, errors.New("failed to parse logo position file '" +
+			filePath + "' - " + err.Error())
+	}
+
+	return file, nil
+}
+
+// SetLogoPosition creates or overwrites the logo position file for the
+// specified game.
+func SetLogoPosition(config SetLogoPositionConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	filePath, err := config.TargetDetails.LogoPositionFilePath()
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(LogoPositionFile{
+		Version:  currentLogoPositionVersion,
+		Position: config.Position,
+	})
+	if err != nil {
+		return err
+	}
+
+	return locations.FileSystemOf(config.TargetDetails.DataVerifier).WriteFile(filePath, raw, config.Mode)
+}
+
+// RemoveLogoPosition removes the logo position file for the specified
+// game. It does not return an error if the file does not exist.
+func RemoveLogoPosition(details ImageDetails) error {
+	filePath, err := details.LogoPositionFilePath()
+	if err != nil {
+		return err
+	}
+
+	err = locations.FileSystemOf(details.DataVerifier).Remove(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package grid
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestSetLogoPosition(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	details := ImageDetails{
+		DataVerifier:       dv,
+		OwnerUserId:        testUserId,
+		GameName:           "Pikmin",
+		GameExecutablePath: `"D:\Program Files\Dolphin\Dolphin.exe"`,
+		Slot:               LogoSlot,
+	}
+
+	position := LogoPosition{
+		PinnedPosition: UpperCenter,
+		WidthPct:       50,
+		HeightPct:      42.5,
+	}
+
+	err := SetLogoPosition(SetLogoPositionConfig{
+		TargetDetails: details,
+		Position:      position,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	file, err := ReadLogoPosition(details)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if file.Version != currentLogoPositionVersion {
+		t.Fatal("Unexpected logo position version -", file.Version)
+	}
+
+	if file.Position != position {
+		t.Fatal("Logo position does not match - got", file.Position)
+	}
+
+	filePath, err := details.LogoPositionFilePath()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if path.Base(filePath) != "11271507026838028288.json" {
+		t.Fatal("Unexpected logo position file name - '" + path.Base(filePath) + "'")
+	}
+}
+
+func TestSetLogoPositionInvalid(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	err := SetLogoPosition(SetLogoPositionConfig{
+		TargetDetails: ImageDetails{
+			DataVerifier: dv,
+			OwnerUserId:  testUserId,
+			GameName:     "Pikmin",
+		},
+		Position: LogoPosition{
+			PinnedPosition: "Sideways",
+			WidthPct:       50,
+			HeightPct:      50,
+		},
+	})
+	if err == nil {
+		t.Fatal("Invalid pinned position did not produce an error")
+	}
+}
+
+func TestRemoveImageRemovesLogoPosition(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	details := ImageDetails{
+		DataVerifier:       dv,
+		OwnerUserId:        testUserId,
+		GameName:           "Pikmin",
+		GameExecutablePath: "/usr/bin/dolphin-emu",
+		Slot:               LogoSlot,
+	}
+
+	logoPath, err := details.FilePath(".png")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = ioutil.WriteFile(logoPath, []byte("not really a png"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = SetLogoPosition(SetLogoPositionConfig{
+		TargetDetails: details,
+		Position: LogoPosition{
+			PinnedPosition: BottomLeft,
+			WidthPct:       100,
+			HeightPct:      100,
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = RemoveImage(RemoveConfig{
+		TargetDetails: details,
+		FileExtension: ".png",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	positionPath, err := details.LogoPositionFilePath()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, p := range []string{logoPath, positionPath} {
+		_, statErr := os.Stat(p)
+		if statErr == nil {
+			t.Fatal("File was not removed - '" + p + "'")
+		}
+	}
+}
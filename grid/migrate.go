@@ -0,0 +1,142 @@
+package grid
+
+import (
+	"errors"
+	"path"
+
+	"github.com/stephen-fox/steamutil/locations"
+)
+
+// MigrateConfig configures the legacy file name migration operation.
+type MigrateConfig struct {
+	// DataVerifier is used to get the grid images directory and
+	// shortcuts file paths.
+	DataVerifier locations.DataVerifier
+
+	// OwnerUserId is the Steam user ID whose grid directory
+	// is migrated.
+	OwnerUserId string
+
+	// KeepOriginals specifies whether or not the legacy files should
+	// be copied rather than renamed.
+	KeepOriginals bool
+
+	// OverwriteExisting specifies whether or not files that already
+	// exist with the new name should be overwritten.
+	OverwriteExisting bool
+
+	// DryRun specifies whether or not the migration should only
+	// report what it would do.
+	DryRun bool
+}
+
+// Validate returns a non-nil error if the MigrateConfig is invalid.
+func (o *MigrateConfig) Validate() error {
+	if o.DataVerifier == nil {
+		return errors.New("the DataVerifier cannot be nil")
+	}
+
+	ownerUserId, err := parseOwnerUserId(o.OwnerUserId)
+	if err != nil {
+		return err
+	}
+	o.OwnerUserId = ownerUserId
+
+	return nil
+}
+
+// MigrateResult describes the outcome of a migration.
+type MigrateResult struct {
+	// Migrated maps the paths of legacy files to their new paths.
+	Migrated map[string]string
+
+	// Skipped maps the paths of legacy files that were not migrated
+	// because their new path already exists to the new path.
+	Skipped map[string]string
+
+	// Unresolved are the paths of legacy files whose ID does not
+	// match any of the user's shortcuts.
+	Unresolved []string
+}
+
+// MigrateLegacyFileNames renames (or copies) grid files that are named with
+// a legacy 64-bit non-Steam game ID to the app ID based names that current
+// versions of Steam use. Each legacy ID is resolved to a shortcut in the
+// user's shortcuts file. Files that cannot be resolved are reported in
+// the result's Unresolved field.
+func MigrateLegacyFileNames(config MigrateConfig) (MigrateResult, error) {
+	err := config.Validate()
+	if err != nil {
+		return MigrateResult{}, err
+	}
+
+	inventory, err := TakeInventory(InventoryConfig{
+		DataVerifier: config.DataVerifier,
+		OwnerUserId:  config.OwnerUserId,
+	})
+	if err != nil {
+		return MigrateResult{}, err
+	}
+
+	fs := locations.FileSystemOf(config.DataVerifier)
+
+	result := MigrateResult{
+		Migrated: make(map[string]string),
+		Skipped:  make(map[string]string),
+	}
+
+	for _, entry := range inventory.Entries {
+		if entry.IdKind != LegacyGameId {
+			continue
+		}
+
+		if entry.Shortcut == nil {
+			result.Unresolved = append(result.Unresolved, entry.Path)
+			continue
+		}
+
+		newName := entry.Shortcut.AppId() + entry.Slot.Suffix() + entry.Extension
+		if entry.IsLogoPosition {
+			newName = entry.Shortcut.AppId() + entry.Extension
+		}
+
+		newPath := path.Join(path.Dir(entry.Path), newName)
+
+		if !config.OverwriteExisting {
+			_, statErr := fs.Stat(newPath)
+			if statErr == nil {
+				result.Skipped[entry.Path] = newPath
+				continue
+			}
+		}
+
+		if !config.DryRun {
+			err := migrateFile(fs, entry.Path, newPath, config.KeepOriginals)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		result.Migrated[entry.Path] = newPath
+	}
+
+	return result, nil
+}
+
+func migrateFile(fs locations.FileSystem, oldPath string, newPath string, keepOriginal bool) error {
+	if !keepOriginal {
+		return fs.Rename(oldPath, newPath)
+	}
+
+	info, err := fs.Stat(oldPath)
+	if err != nil {
+		return err
+	}
+
+	data, err := fs.ReadFile(oldPath)
+	if err != nil {
+		return err
+	}
+
+	return fs.WriteFile(newPath, data, info.Mode())
+}
@@ -0,0 +1,91 @@
+package grid
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+func TestMigrateLegacyFileNames(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	s := shortcuts.Shortcut{
+		AppName: "Chess",
+		ExePath: "/Applications/Chess.app",
+	}
+
+	writeTestShortcuts(t, dv, []shortcuts.Shortcut{s})
+
+	gridDirPath := locations.GridDirPath(dv.RootDirPath(), testUserId)
+
+	unknownLegacyId := (&shortcuts.Shortcut{AppName: "Gone"}).LegacyId()
+
+	for _, name := range []string{s.LegacyId() + ".png", s.LegacyId() + "p.jpg", unknownLegacyId + ".png"} {
+		err := ioutil.WriteFile(path.Join(gridDirPath, name), []byte(name), 0600)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	config := MigrateConfig{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		DryRun:       true,
+	}
+
+	result, err := MigrateLegacyFileNames(config)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(result.Migrated) != 2 {
+		t.Fatal("Unexpected dry run result -", result.Migrated)
+	}
+
+	_, err = os.Stat(path.Join(gridDirPath, s.LegacyId()+".png"))
+	if err != nil {
+		t.Fatal("Dry run modified the grid directory - " + err.Error())
+	}
+
+	config.DryRun = false
+
+	result, err = MigrateLegacyFileNames(config)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(result.Unresolved) != 1 || path.Base(result.Unresolved[0]) != unknownLegacyId+".png" {
+		t.Fatal("Unexpected unresolved files -", result.Unresolved)
+	}
+
+	expected := map[string]string{
+		s.LegacyId() + ".png":  s.AppId() + ".png",
+		s.LegacyId() + "p.jpg": s.AppId() + "p.jpg",
+	}
+
+	for from, to := range expected {
+		newPath := result.Migrated[path.Join(gridDirPath, from)]
+		if path.Base(newPath) != to {
+			t.Fatal("Expected '" + from + "' to be migrated to '" + to + "' - got '" + newPath + "'")
+		}
+
+		data, err := ioutil.ReadFile(newPath)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if string(data) != from {
+			t.Fatal("Unexpected migrated file contents - '" + string(data) + "'")
+		}
+
+		_, err = os.Stat(path.Join(gridDirPath, from))
+		if err == nil {
+			t.Fatal("Legacy file was not renamed - '" + from + "'")
+		}
+	}
+}
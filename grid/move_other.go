@@ -0,0 +1,15 @@
+//go:build !windows
+// +build !windows
+
+package grid
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isCrossDeviceError returns true if err means that a file could not be
+// renamed because the destination is on a different device.
+func isCrossDeviceError(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
@@ -0,0 +1,18 @@
+package grid
+
+import (
+	"errors"
+	"syscall"
+)
+
+const (
+	// errorNotSameDevice is ERROR_NOT_SAME_DEVICE, which is returned
+	// when a file is moved to a different volume.
+	errorNotSameDevice syscall.Errno = 17
+)
+
+// isCrossDeviceError returns true if err means that a file could not be
+// renamed because the destination is on a different device.
+func isCrossDeviceError(err error) bool {
+	return errors.Is(err, errorNotSameDevice)
+}
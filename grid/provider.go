@@ -0,0 +1,218 @@
+package grid
+
+import (
+	"errors"
+	"image"
+	"io"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+// Query describes the game that artwork is being looked up for.
+// Providers use whichever fields they support.
+type Query struct {
+	// Title is the game's name.
+	Title string
+
+	// AppId is the game's app ID. This is either a Steam app ID,
+	// or the app ID of a non-Steam game shortcut.
+	AppId string
+
+	// ExePath is the game's executable path.
+	ExePath string
+}
+
+// Candidate is an image that a Provider found for a game.
+type Candidate struct {
+	// Slot is the artwork slot that the image is intended for.
+	Slot Slot
+
+	// Source describes where the image came from, such as a file
+	// path or URL.
+	Source string
+
+	// Size is the image's dimensions, if known.
+	Size image.Point
+
+	// Open opens the image for reading.
+	Open func() (io.ReadCloser, error)
+}
+
+// Provider looks up artwork for games.
+type Provider interface {
+	// Lookup returns the candidate images for a game. Candidates for
+	// the same Slot are ordered from best to worst match. An empty
+	// slice is returned if no artwork was found.
+	Lookup(query Query) ([]Candidate, error)
+}
+
+// BestCandidates returns the best Candidate for each Slot.
+func BestCandidates(candidates []Candidate) map[Slot]Candidate {
+	best := make(map[Slot]Candidate)
+
+	for _, c := range candidates {
+		_, exists := best[c.Slot]
+		if !exists {
+			best[c.Slot] = c
+		}
+	}
+
+	return best
+}
+
+// ApplyConfig configures the apply best matches operation.
+type ApplyConfig struct {
+	// DataVerifier is used to get the grid images directory and
+	// shortcuts file paths.
+	DataVerifier locations.DataVerifier
+
+	// OwnerUserId is the Steam user ID whose shortcuts receive
+	// the artwork.
+	OwnerUserId string
+
+	// Provider looks up the artwork.
+	Provider Provider
+
+	// Slots are the artwork slots to apply. Defaults to all slots.
+	Slots []Slot
+
+	// OverwriteExisting specifies whether or not existing grid images
+	// should be overwritten.
+	OverwriteExisting bool
+}
+
+// Validate returns a non-nil error if the ApplyConfig is invalid.
+func (o *ApplyConfig) Validate() error {
+	if o.DataVerifier == nil {
+		return errors.New("the DataVerifier cannot be nil")
+	}
+
+	ownerUserId, err := parseOwnerUserId(o.OwnerUserId)
+	if err != nil {
+		return err
+	}
+	o.OwnerUserId = ownerUserId
+
+	if o.Provider == nil {
+		return errors.New("the Provider cannot be nil")
+	}
+
+	if len(o.Slots) == 0 {
+		o.Slots = Slots()
+	}
+
+	return nil
+}
+
+// ApplyResult is the result of applying artwork to a single shortcut.
+type ApplyResult struct {
+	// Shortcut is the shortcut that the artwork was applied to.
+	Shortcut shortcuts.Shortcut
+
+	// Applied maps the artwork slots that were applied to the
+	// Source of the image that was used.
+	Applied map[Slot]string
+
+	// Err is non-nil if looking up or applying the artwork failed.
+	Err error
+}
+
+// ApplyBestMatches looks up artwork for each of a user's shortcuts and
+// adds the best match for each of the configured slots. Failures for
+// individual shortcuts are reported in the corresponding ApplyResult
+// rather than stopping the operation.
+func ApplyBestMatches(config ApplyConfig) ([]ApplyResult, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	scs, err := readUserShortcuts(config.DataVerifier, config.OwnerUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ApplyResult
+
+	for i := range scs {
+		result := ApplyResult{
+			Shortcut: scs[i],
+			Applied:  make(map[Slot]string),
+		}
+
+		result.Err = applyBestMatch(config, &scs[i], result.Applied)
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func applyBestMatch(config ApplyConfig, s *shortcuts.Shortcut, applied map[Slot]string) error {
+	candidates, err := config.Provider.Lookup(Query{
+		Title:   s.AppName,
+		AppId:   s.AppId(),
+		ExePath: s.ExePath,
+	})
+	if err != nil {
+		return err
+	}
+
+	best := BestCandidates(candidates)
+
+	for _, slot := range config.Slots {
+		candidate, ok := best[slot]
+		if !ok {
+			continue
+		}
+
+		filePath, err := addCandidate(config, s, candidate)
+		if err != nil {
+			return errors.New("failed to add " + slot.String() + " image from '" +
+				candidate.Source + "' - " + err.Error())
+		}
+
+		if len(filePath) > 0 {
+			applied[slot] = candidate.Source
+		}
+	}
+
+	return nil
+}
+
+// addCandidate adds the candidate image for the shortcut, returning the
+// path of the resulting file. An empty path is returned if the shortcut
+// already has an image in the candidate's slot and OverwriteExisting is
+// false. In that case, the candidate is not opened.
+func addCandidate(config ApplyConfig, s *shortcuts.Shortcut, candidate Candidate) (string, error) {
+	details := ImageDetails{
+		DataVerifier: config.DataVerifier,
+		OwnerUserId:  config.OwnerUserId,
+		Shortcut:     s,
+		Slot:         candidate.Slot,
+	}
+
+	if !config.OverwriteExisting {
+		existing, err := slotImagePaths(details)
+		if err != nil {
+			return "", err
+		}
+
+		if len(existing) > 0 {
+			return "", nil
+		}
+	}
+
+	rc, err := candidate.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	return addImage(AddConfig{
+		ResultDetails:     details,
+		ImageSource:       rc,
+		OverwriteExisting: config.OverwriteExisting,
+	})
+}
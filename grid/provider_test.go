@@ -0,0 +1,296 @@
+package grid
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+func TestMatchScore(t *testing.T) {
+	if matchScore("The Legend of Zelda", "legend-of-zelda") < defaultMinMatchScore {
+		t.Fatal("Similar names did not match")
+	}
+
+	if matchScore("Pikmin", "Pikmin 2") < defaultMinMatchScore {
+		t.Fatal("Similar names did not match")
+	}
+
+	if matchScore("Pikmin", "Chess") >= defaultMinMatchScore {
+		t.Fatal("Different names matched")
+	}
+
+	if matchScore("Half-Life: 2", "half life 2") != 1 {
+		t.Fatal("Names that only differ by punctuation are not an exact match")
+	}
+}
+
+func TestFilesystemProvider_Lookup(t *testing.T) {
+	rootDirPath, err := ioutil.TempDir("", "steamutil-grid-provider-test")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(rootDirPath)
+
+	for _, name := range []string{"Pikmin 2", "Chess", "400"} {
+		err := os.Mkdir(path.Join(rootDirPath, name), 0700)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	writeTestImage(t, path.Join(rootDirPath, "Pikmin 2"), "hero.png", Png)
+	writeTestImage(t, path.Join(rootDirPath, "Pikmin 2"), "hero-alt.png", Png)
+	writeTestImage(t, path.Join(rootDirPath, "Pikmin 2"), "Portrait.jpg", Jpeg)
+	writeTestImage(t, path.Join(rootDirPath, "Pikmin 2"), "notes.png", Png)
+	writeTestImage(t, path.Join(rootDirPath, "400"), "logo.png", Png)
+
+	p, err := NewFilesystemProvider(FilesystemProviderConfig{
+		RootDirPath: rootDirPath,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	candidates, err := p.Lookup(Query{
+		Title: "pikmin II",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(candidates) != 3 {
+		t.Fatal("Unexpected number of candidates -", len(candidates))
+	}
+
+	best := BestCandidates(candidates)
+
+	if path.Base(best[HeroSlot].Source) != "hero.png" {
+		t.Fatal("Unexpected best hero image - '" + best[HeroSlot].Source + "'")
+	}
+
+	if path.Base(best[PortraitSlot].Source) != "Portrait.jpg" {
+		t.Fatal("Unexpected best portrait image - '" + best[PortraitSlot].Source + "'")
+	}
+
+	candidates, err = p.Lookup(Query{
+		Title: "Portal",
+		AppId: "400",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(candidates) != 1 || candidates[0].Slot != LogoSlot {
+		t.Fatal("Unexpected candidates for app ID lookup -", candidates)
+	}
+
+	candidates, err = p.Lookup(Query{
+		Title: "Something Else Entirely",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(candidates) != 0 {
+		t.Fatal("Unexpected candidates for unknown game -", candidates)
+	}
+}
+
+func TestHttpProvider_Lookup(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	const apiKey = "secret"
+
+	api := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+apiKey {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Write([]byte(body))
+		}
+	}
+
+	mux.HandleFunc("/api/v2/search/autocomplete/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/search/autocomplete/Pikmin 2" {
+			api(`{"success":true,"data":[]}`)(w, r)
+			return
+		}
+
+		api(`{"success":true,"data":[{"id":42,"name":"Pikmin 2"}]}`)(w, r)
+	})
+	mux.HandleFunc("/api/v2/grids/game/42", api(`{"success":true,"data":[`+
+		`{"id":1,"url":"`+server.URL+`/images/portrait.png","width":600,"height":900},`+
+		`{"id":2,"url":"`+server.URL+`/images/wide.png","width":920,"height":430}]}`))
+	mux.HandleFunc("/api/v2/heroes/game/42", api(`{"success":true,"data":[`+
+		`{"id":3,"url":"`+server.URL+`/images/hero.png","width":1920,"height":620}]}`))
+	mux.HandleFunc("/api/v2/logos/game/42", api(`{"success":true,"data":[]}`))
+	mux.HandleFunc("/api/v2/icons/steam/400", api(`{"success":true,"data":[`+
+		`{"id":4,"url":"`+server.URL+`/images/icon.png","width":256,"height":256}]}`))
+	mux.HandleFunc("/images/", func(w http.ResponseWriter, r *http.Request) {
+		if len(r.Header.Get("Authorization")) > 0 {
+			t.Error("API key was sent to the image host")
+		}
+
+		w.Write([]byte("\x89PNG\r\n\x1a\n"))
+	})
+
+	p, err := NewHttpProvider(HttpProviderConfig{
+		BaseUrl: server.URL + "/api/v2/",
+		ApiKey:  apiKey,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	candidates, err := p.Lookup(Query{
+		Title: "Pikmin 2",
+		AppId: "2624352236",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(candidates) != 3 {
+		t.Fatal("Unexpected number of candidates -", len(candidates))
+	}
+
+	best := BestCandidates(candidates)
+
+	for slot, name := range map[Slot]string{PortraitSlot: "portrait.png", WideSlot: "wide.png", HeroSlot: "hero.png"} {
+		if path.Base(best[slot].Source) != name {
+			t.Fatal("Unexpected", slot, "candidate - '"+best[slot].Source+"'")
+		}
+	}
+
+	rc, err := best[HeroSlot].Open()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer rc.Close()
+
+	format, err := DetectFormat(rc)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if format != Png {
+		t.Fatal("Unexpected image format -", format)
+	}
+
+	candidates, err = p.Lookup(Query{
+		Title: "Portal",
+		AppId: "400",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(candidates) != 1 || candidates[0].Slot != IconSlot {
+		t.Fatal("Unexpected candidates for Steam app lookup -", candidates)
+	}
+}
+
+func TestApplyBestMatches(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	artworkDirPath := path.Join(dv.RootDirPath(), "artwork")
+	gameDirPath := path.Join(artworkDirPath, "Chess")
+
+	err := os.MkdirAll(gameDirPath, 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	writeTestImage(t, gameDirPath, "hero.png", Png)
+	writeTestImage(t, gameDirPath, "icon.png", Png)
+
+	scs := []shortcuts.Shortcut{
+		{
+			AppName: "Chess",
+			ExePath: "/Applications/Chess.app",
+		},
+		{
+			Id:      1,
+			AppName: "Automator",
+			ExePath: "/Applications/Automator.app",
+		},
+	}
+
+	writeTestShortcuts(t, dv, scs)
+
+	p, err := NewFilesystemProvider(FilesystemProviderConfig{
+		RootDirPath: artworkDirPath,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	results, err := ApplyBestMatches(ApplyConfig{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		Provider:     p,
+		Slots:        []Slot{HeroSlot},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(results) != 2 {
+		t.Fatal("Unexpected number of results -", len(results))
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			t.Fatal(result.Err.Error())
+		}
+	}
+
+	if len(results[0].Applied) != 1 || len(results[1].Applied) != 0 {
+		t.Fatal("Unexpected applied artwork -", results[0].Applied, results[1].Applied)
+	}
+
+	heroPath, err := (&ImageDetails{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		Shortcut:     &scs[0],
+		Slot:         HeroSlot,
+	}).FilePath(".png")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = os.Stat(heroPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	results, err = ApplyBestMatches(ApplyConfig{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		Provider:     p,
+		Slots:        []Slot{HeroSlot},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			t.Fatal(result.Err.Error())
+		}
+
+		if len(result.Applied) != 0 {
+			t.Fatal("Existing artwork was reported as applied -", result.Applied)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package grid
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"math"
+)
+
+const (
+	// NoFit leaves the image's dimensions unchanged.
+	NoFit FitMode = iota
+
+	// FitStretch scales the image to the recommended size without
+	// preserving its aspect ratio.
+	FitStretch
+
+	// FitCrop scales the image to cover the recommended size while
+	// preserving its aspect ratio, and then crops the overflow equally
+	// from both sides.
+	FitCrop
+
+	// FitLetterbox scales the image to fit inside the recommended size
+	// while preserving its aspect ratio, and pads the remaining space
+	// with transparent pixels.
+	FitLetterbox
+)
+
+const (
+	// aspectRatioTolerance is the maximum relative difference between
+	// two aspect ratios for them to be considered equal.
+	aspectRatioTolerance = 0.01
+)
+
+// FitMode specifies how an image is adjusted to a Slot's
+// recommended size.
+type FitMode int
+
+// RecommendedSize returns the image dimensions that Steam expects for
+// the Slot. A zero value is returned if the Slot has no fixed size.
+func (o Slot) RecommendedSize() image.Point {
+	switch o {
+	case WideSlot:
+		return image.Pt(460, 215)
+	case PortraitSlot:
+		return image.Pt(600, 900)
+	case HeroSlot:
+		return image.Pt(1920, 620)
+	case IconSlot:
+		return image.Pt(256, 256)
+	}
+
+	return image.Point{}
+}
+
+// MatchesRecommendedSize returns true if an image of the specified size
+// has the same aspect ratio as the Slot's recommended size. Images that
+// are a multiple of the recommended size are considered a match. It
+// always returns true for Slots that have no recommended size.
+func (o Slot) MatchesRecommendedSize(size image.Point) bool {
+	recommended := o.RecommendedSize()
+	if recommended.X == 0 || recommended.Y == 0 {
+		return true
+	}
+
+	if size.X <= 0 || size.Y <= 0 {
+		return false
+	}
+
+	expected := float64(recommended.X) / float64(recommended.Y)
+	actual := float64(size.X) / float64(size.Y)
+
+	return math.Abs(actual-expected)/expected <= aspectRatioTolerance
+}
+
+// ImageSize returns the dimensions of an encoded image without decoding
+// the entire image.
+func ImageSize(raw []byte) (image.Point, error) {
+	format, err := detectFormat(raw)
+	if err != nil {
+		return image.Point{}, err
+	}
+
+	if format == WebP {
+		return webPSize(raw)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return image.Point{}, err
+	}
+
+	return image.Pt(config.Width, config.Height), nil
+}
+
+// webPSize parses the dimensions of a WebP image from its header. The
+// standard library does not include a WebP decoder.
+func webPSize(raw []byte) (image.Point, error) {
+	if len(raw) < 30 {
+		return image.Point{}, errors.New("webp image header is too short")
+	}
+
+	switch string(raw[12:16]) {
+	case "VP8 ":
+		width := binary.LittleEndian.Uint16(raw[26:28]) & 0x3fff
+		height := binary.LittleEndian.Uint16(raw[28:30]) & 0x3fff
+
+		return image.Pt(int(width), int(height)), nil
+	case "VP8L":
+		bits := binary.LittleEndian.Uint32(raw[21:25])
+		width := bits&0x3fff + 1
+		height := (bits>>14)&0x3fff + 1
+
+		return image.Pt(int(width), int(height)), nil
+	case "VP8X":
+		width := uint32(raw[24]) | uint32(raw[25])<<8 | uint32(raw[26])<<16
+		height := uint32(raw[27]) | uint32(raw[28])<<8 | uint32(raw[29])<<16
+
+		return image.Pt(int(width)+1, int(height)+1), nil
+	}
+
+	return image.Point{}, errors.New("unknown webp chunk type '" + string(raw[12:16]) + "'")
+}
+
+// fitImage scales src to the specified size according to the FitMode.
+func fitImage(src image.Image, size image.Point, mode FitMode) image.Image {
+	srcBounds := src.Bounds()
+
+	rgba := image.NewRGBA(image.Rect(0, 0, srcBounds.Dx(), srcBounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, srcBounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+
+	srcRect := rgba.Bounds()
+	dstRect := dst.Bounds()
+
+	srcW := float64(srcRect.Dx())
+	srcH := float64(srcRect.Dy())
+
+	switch mode {
+	case FitCrop:
+		scale := math.Max(float64(size.X)/srcW, float64(size.Y)/srcH)
+		cropW := int(math.Round(float64(size.X) / scale))
+		cropH := int(math.Round(float64(size.Y) / scale))
+		x := (srcRect.Dx() - cropW) / 2
+		y := (srcRect.Dy() - cropH) / 2
+		srcRect = image.Rect(x, y, x+cropW, y+cropH)
+	case FitLetterbox:
+		scale := math.Min(float64(size.X)/srcW, float64(size.Y)/srcH)
+		boxW := int(math.Round(srcW * scale))
+		boxH := int(math.Round(srcH * scale))
+		x := (size.X - boxW) / 2
+		y := (size.Y - boxH) / 2
+		dstRect = image.Rect(x, y, x+boxW, y+boxH)
+	}
+
+	scaleBilinear(dst, dstRect, rgba, srcRect)
+
+	return dst
+}
+
+// scaleBilinear scales the srcRect region of src into the dstRect region
+// of dst using bilinear interpolation of premultiplied color values.
+func scaleBilinear(dst *image.RGBA, dstRect image.Rectangle, src *image.RGBA, srcRect image.Rectangle) {
+	if dstRect.Empty() || srcRect.Empty() {
+		return
+	}
+
+	xRatio := float64(srcRect.Dx()) / float64(dstRect.Dx())
+	yRatio := float64(srcRect.Dy()) / float64(dstRect.Dy())
+
+	maxX := srcRect.Max.X - 1
+	maxY := srcRect.Max.Y - 1
+
+	for dy := dstRect.Min.Y; dy < dstRect.Max.Y; dy++ {
+		sy := (float64(dy-dstRect.Min.Y)+0.5)*yRatio - 0.5 + float64(srcRect.Min.Y)
+		y0 := clamp(int(math.Floor(sy)), srcRect.Min.Y, maxY)
+		y1 := clamp(y0+1, srcRect.Min.Y, maxY)
+		fy := clampFloat(sy - float64(y0))
+
+		for dx := dstRect.Min.X; dx < dstRect.Max.X; dx++ {
+			sx := (float64(dx-dstRect.Min.X)+0.5)*xRatio - 0.5 + float64(srcRect.Min.X)
+			x0 := clamp(int(math.Floor(sx)), srcRect.Min.X, maxX)
+			x1 := clamp(x0+1, srcRect.Min.X, maxX)
+			fx := clampFloat(sx - float64(x0))
+
+			p00 := src.PixOffset(x0, y0)
+			p10 := src.PixOffset(x1, y0)
+			p01 := src.PixOffset(x0, y1)
+			p11 := src.PixOffset(x1, y1)
+			d := dst.PixOffset(dx, dy)
+
+			for c := 0; c < 4; c++ {
+				top := float64(src.Pix[p00+c])*(1-fx) + float64(src.Pix[p10+c])*fx
+				bottom := float64(src.Pix[p01+c])*(1-fx) + float64(src.Pix[p11+c])*fx
+				dst.Pix[d+c] = uint8(math.Round(top*(1-fy) + bottom*fy))
+			}
+		}
+	}
+}
+
+func clamp(v int, min int, max int) int {
+	if v < min {
+		return min
+	}
+
+	if v > max {
+		return max
+	}
+
+	return v
+}
+
+func clampFloat(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+
+	if v > 1 {
+		return 1
+	}
+
+	return v
+}
@@ -0,0 +1,120 @@
+package grid
+
+import (
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSlot_MatchesRecommendedSize(t *testing.T) {
+	if !WideSlot.MatchesRecommendedSize(image.Pt(920, 430)) {
+		t.Fatal("Double sized wide image should match")
+	}
+
+	if WideSlot.MatchesRecommendedSize(image.Pt(600, 900)) {
+		t.Fatal("Portrait image should not match wide slot")
+	}
+
+	if !LogoSlot.MatchesRecommendedSize(image.Pt(123, 45)) {
+		t.Fatal("Logo slot should match any size")
+	}
+}
+
+func TestImageSizeWebP(t *testing.T) {
+	// A VP8X header for a 1920x620 image.
+	raw := []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00\x10\x00\x00\x00" +
+		"\x7f\x07\x00\x6b\x02\x00")
+
+	size, err := ImageSize(raw)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if size != image.Pt(1920, 620) {
+		t.Fatal("Unexpected webp size -", size)
+	}
+}
+
+func TestFitImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for x := 0; x < 100; x++ {
+		for y := 0; y < 100; y++ {
+			src.Set(x, y, color.RGBA{G: 255, A: 255})
+		}
+	}
+
+	target := HeroSlot.RecommendedSize()
+
+	for _, mode := range []FitMode{FitStretch, FitCrop, FitLetterbox} {
+		result := fitImage(src, target, mode)
+
+		if result.Bounds().Size() != target {
+			t.Fatal("Unexpected size for mode", mode, "-", result.Bounds().Size())
+		}
+
+		_, _, _, centerAlpha := result.At(target.X/2, target.Y/2).RGBA()
+		if centerAlpha == 0 {
+			t.Fatal("Center pixel is transparent for mode", mode)
+		}
+
+		_, _, _, cornerAlpha := result.At(0, 0).RGBA()
+		if mode == FitLetterbox && cornerAlpha != 0 {
+			t.Fatal("Letterboxed image corner is not transparent")
+		} else if mode != FitLetterbox && cornerAlpha == 0 {
+			t.Fatal("Corner pixel is transparent for mode", mode)
+		}
+	}
+}
+
+func TestAddImageFit(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	sourcePath := writeTestImage(t, dv.RootDirPath(), "cover.jpg", Jpeg)
+
+	details := ImageDetails{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		GameName:     "Chess",
+		Slot:         PortraitSlot,
+	}
+
+	var mismatches int
+
+	err := AddImage(AddConfig{
+		ResultDetails:   details,
+		ImageSourcePath: sourcePath,
+		Fit:             FitCrop,
+		OnSizeMismatch: func(slot Slot, actual image.Point, recommended image.Point) {
+			mismatches++
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if mismatches != 1 {
+		t.Fatal("Unexpected number of size mismatches -", mismatches)
+	}
+
+	resultPath, err := details.FilePath(Png.Extension())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	raw, err := ioutil.ReadFile(resultPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	size, err := ImageSize(raw)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if size != PortraitSlot.RecommendedSize() {
+		t.Fatal("Unexpected resulting image size -", size)
+	}
+}
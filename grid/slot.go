@@ -0,0 +1,71 @@
+package grid
+
+const (
+	// WideSlot is the horizontal capsule shown in the library's
+	// grid view. This is the default slot.
+	WideSlot Slot = iota
+
+	// PortraitSlot is the vertical capsule shown in the library's
+	// grid view.
+	PortraitSlot
+
+	// HeroSlot is the banner displayed at the top of a game's
+	// library page.
+	HeroSlot
+
+	// LogoSlot is the logo displayed on top of the hero image.
+	LogoSlot
+
+	// IconSlot is the small icon displayed in the library's list view.
+	IconSlot
+)
+
+// Slot identifies where Steam displays a piece of artwork. Each slot
+// maps to a different file name suffix in the grid directory.
+type Slot int
+
+// String returns a human-readable name for the Slot.
+func (o Slot) String() string {
+	switch o {
+	case WideSlot:
+		return "wide"
+	case PortraitSlot:
+		return "portrait"
+	case HeroSlot:
+		return "hero"
+	case LogoSlot:
+		return "logo"
+	case IconSlot:
+		return "icon"
+	}
+
+	return "unknown"
+}
+
+// Suffix returns the string that Steam appends to a game ID to form
+// the file name for the Slot (excluding the file extension).
+func (o Slot) Suffix() string {
+	switch o {
+	case PortraitSlot:
+		return "p"
+	case HeroSlot:
+		return "_hero"
+	case LogoSlot:
+		return "_logo"
+	case IconSlot:
+		return "_icon"
+	}
+
+	return ""
+}
+
+// Slots returns all of the known artwork slots.
+func Slots() []Slot {
+	return []Slot{
+		WideSlot,
+		PortraitSlot,
+		HeroSlot,
+		LogoSlot,
+		IconSlot,
+	}
+}
@@ -0,0 +1,155 @@
+package grid
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/naming"
+)
+
+// UserFilter decides whether or not a grid operation should be applied
+// to the specified Steam user ID. A nil UserFilter selects every user.
+type UserFilter func(userId string) bool
+
+// OnlyUsers returns a UserFilter that selects the specified Steam user IDs.
+// The IDs may be in any of the formats accepted by naming.ParseUserId. An
+// error is returned if an ID is malformed or does not belong to a user.
+func OnlyUsers(userIds ...string) (UserFilter, error) {
+	selected := make(map[string]bool)
+	for _, userId := range userIds {
+		accountId, err := naming.ParseUserId(userId)
+		if err != nil {
+			return nil, err
+		}
+
+		selected[accountId] = true
+	}
+
+	return func(userId string) bool {
+		return selected[userId]
+	}, nil
+}
+
+// UserResult is the outcome of a grid operation for a single user.
+type UserResult struct {
+	// Paths are the paths of the files that were affected by
+	// the operation.
+	Paths []string
+
+	// Err is non-nil if the operation failed for the user.
+	Err error
+}
+
+// AddImageForUsers adds an image to the grid directory of each local user
+// that is selected by the filter. The OwnerUserId of the config's
+// ResultDetails is ignored. A user's grid directory is created if it does
+// not exist.
+//
+// A failure for one user does not stop the operation for the remaining
+// users. Instead, the failure is recorded in the user's UserResult. The
+// returned error is only non-nil if the users could not be listed, or if
+// the image source could not be read.
+func AddImageForUsers(config AddConfig, filter UserFilter) (map[string]UserResult, error) {
+	if config.ResultDetails.DataVerifier == nil {
+		return nil, errors.New("the DataVerifier cannot be nil")
+	}
+
+	userIds, err := filterUsers(config.ResultDetails.DataVerifier, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+
+	if config.ImageSource != nil {
+		raw, err = ioutil.ReadAll(config.ImageSource)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make(map[string]UserResult)
+
+	for _, userId := range userIds {
+		userConfig := config
+		userConfig.ResultDetails.OwnerUserId = userId
+		if raw != nil {
+			userConfig.ImageSource = bytes.NewReader(raw)
+		}
+
+		var result UserResult
+
+		filePath, err := addImage(userConfig)
+		if err != nil {
+			result.Err = err
+		} else if len(filePath) > 0 {
+			result.Paths = []string{filePath}
+		}
+
+		results[userId] = result
+	}
+
+	return results, nil
+}
+
+// RemoveImageForUsers removes images from the grid directory of each local
+// user that is selected by the filter. The OwnerUserId of the config's
+// TargetDetails is ignored. Users that do not have a grid directory are
+// reported as having no files removed.
+//
+// A failure for one user does not stop the operation for the remaining
+// users. Instead, the failure is recorded in the user's UserResult. The
+// returned error is only non-nil if the users could not be listed.
+func RemoveImageForUsers(config RemoveConfig, filter UserFilter) (map[string]UserResult, error) {
+	if config.TargetDetails.DataVerifier == nil {
+		return nil, errors.New("the DataVerifier cannot be nil")
+	}
+
+	userIds, err := filterUsers(config.TargetDetails.DataVerifier, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]UserResult)
+
+	for _, userId := range userIds {
+		userConfig := config
+		userConfig.TargetDetails.OwnerUserId = userId
+
+		var result UserResult
+
+		result.Paths, result.Err = RemoveImage(userConfig)
+		if os.IsNotExist(result.Err) {
+			result.Err = nil
+		}
+
+		results[userId] = result
+	}
+
+	return results, nil
+}
+
+// filterUsers returns the sorted IDs of the local users that are selected
+// by the filter.
+func filterUsers(dv locations.DataVerifier, filter UserFilter) ([]string, error) {
+	userIdsToDirPaths, err := dv.UserIdsToDataDirPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var userIds []string
+
+	for userId := range userIdsToDirPaths {
+		if filter == nil || filter(userId) {
+			userIds = append(userIds, userId)
+		}
+	}
+
+	sort.Strings(userIds)
+
+	return userIds, nil
+}
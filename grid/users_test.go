@@ -0,0 +1,118 @@
+package grid
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/locations"
+)
+
+func TestAddImageForUsers(t *testing.T) {
+	dv := newTestDataVerifier(t)
+	defer os.RemoveAll(dv.RootDirPath())
+
+	const otherUserId = "87654321"
+	const excludedUserId = "11111111"
+
+	for _, userId := range []string{otherUserId, excludedUserId} {
+		err := os.MkdirAll(locations.UserIdDirPath(dv.RootDirPath(), userId), 0700)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	sourcePath := writeTestImage(t, dv.RootDirPath(), "cover.png", Png)
+
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer source.Close()
+
+	config := AddConfig{
+		ResultDetails: ImageDetails{
+			DataVerifier: dv,
+			GameName:     "Chess",
+		},
+		ImageSource: source,
+	}
+
+	results, err := AddImageForUsers(config, func(userId string) bool {
+		return userId != excludedUserId
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(results) != 2 {
+		t.Fatal("Unexpected number of results -", results)
+	}
+
+	for _, userId := range []string{testUserId, otherUserId} {
+		result := results[userId]
+		if result.Err != nil {
+			t.Fatal(result.Err.Error())
+		}
+
+		if len(result.Paths) != 1 {
+			t.Fatal("Unexpected paths for user "+userId+" -", result.Paths)
+		}
+
+		_, err := os.Stat(result.Paths[0])
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	_, err = os.Stat(locations.GridDirPath(dv.RootDirPath(), excludedUserId))
+	if err == nil {
+		t.Fatal("Grid directory was created for a user that was not selected")
+	}
+
+	removeResults, err := RemoveImageForUsers(RemoveConfig{
+		TargetDetails: config.ResultDetails,
+	}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(removeResults) != 3 {
+		t.Fatal("Unexpected number of remove results -", removeResults)
+	}
+
+	for userId, result := range removeResults {
+		if result.Err != nil {
+			t.Fatal(result.Err.Error())
+		}
+
+		if userId == excludedUserId && len(result.Paths) != 0 {
+			t.Fatal("Unexpected paths removed for user without a grid directory -", result.Paths)
+		}
+
+		if userId != excludedUserId && len(result.Paths) != 1 {
+			t.Fatal("Unexpected paths removed for user "+userId+" -", result.Paths)
+		}
+	}
+}
+
+func TestOnlyUsers(t *testing.T) {
+	filter, err := OnlyUsers("[U:1:12345678]", "76561198047141349")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, userId := range []string{testUserId, "86875621"} {
+		if !filter(userId) {
+			t.Fatal("User " + userId + " was not selected")
+		}
+	}
+
+	if filter("11111111") {
+		t.Fatal("User 11111111 was selected")
+	}
+
+	_, err = OnlyUsers("STEAM_0:1:not-a-number")
+	if err == nil {
+		t.Fatal("Expected an error for a malformed user ID")
+	}
+}
@@ -0,0 +1,58 @@
+// Package steamtest provides helpers for testing code that accesses a
+// Steam data directory.
+package steamtest
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/locations"
+)
+
+const (
+	testDataSubDir = "/.testdata/"
+)
+
+// NewDataVerifier creates a DataVerifier for a temporary Steam data
+// directory that contains a copy of a file from the repository's
+// .testdata directory, such as 'config-vdf/config.vdf'. The directory is
+// removed when the test finishes.
+//
+// The file is copied to filePath, which is relative to the data directory,
+// such as a path generated by locations.ConfigFilePath with an empty data
+// directory path. The test must be run from a package directory at the
+// root of the repository.
+func NewDataVerifier(t *testing.T, filePath string, fixture string) locations.DataVerifier {
+	dataDirPath := t.TempDir()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	raw, err := ioutil.ReadFile(path.Dir(wd) + testDataSubDir + fixture)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	filePath = path.Join(dataDirPath, filePath)
+
+	err = os.MkdirAll(path.Dir(filePath), 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = ioutil.WriteFile(filePath, raw, 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	dv, err := locations.NewDataVerifierForDir(dataDirPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return dv
+}
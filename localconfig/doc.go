@@ -0,0 +1,4 @@
+// Package localconfig provides functionality for working with a Steam
+// user's local configuration file (localconfig.vdf), which stores
+// per-app settings such as launch options and playtime.
+package localconfig
@@ -0,0 +1,274 @@
+package localconfig
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/naming"
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+const (
+	rootKey           = "UserLocalConfigStore"
+	lastPlayedKey     = "LastPlayed"
+	playtimeKey       = "Playtime"
+	playtime2WeeksKey = "Playtime2wks"
+	launchOptionsKey  = "LaunchOptions"
+	cloudKey          = "cloud"
+	lastSyncStateKey  = "last_sync_state"
+)
+
+var (
+	appsKeys = []string{"Software", "Valve", "Steam", "apps"}
+)
+
+// App is the local configuration of a Steam app.
+type App struct {
+	// AppId is the app's Steam app ID.
+	AppId string
+
+	// LastPlayedEpoch is the time that the app was last played, in
+	// seconds since the Unix epoch.
+	LastPlayedEpoch int64
+
+	// PlaytimeMinutes is the total time that the app was played
+	// for, in minutes.
+	PlaytimeMinutes int64
+
+	// PlaytimeTwoWeeksMinutes is the time that the app was played
+	// for in the last two weeks, in minutes.
+	PlaytimeTwoWeeksMinutes int64
+
+	// LaunchOptions are the app's launch options.
+	LaunchOptions string
+
+	// CloudSyncState is the state of the app's last Steam Cloud
+	// sync, such as 'synchronized'.
+	CloudSyncState string
+}
+
+// LocalConfig is a parsed local configuration file. Everything in the
+// file is preserved when it is written, including the sections that
+// are not exposed by LocalConfig.
+type LocalConfig struct {
+	doc *vdf.KeyValue
+}
+
+// Apps returns the local configuration of every app in the file, sorted
+// by app ID.
+func (o *LocalConfig) Apps() []App {
+	apps, ok := o.apps(false)
+	if !ok {
+		return nil
+	}
+
+	var result []App
+
+	for _, entry := range apps.Children {
+		if entry.IsObject {
+			result = append(result, appFromKeyValue(entry))
+		}
+	}
+
+	sort.Slice(result, func(i int, j int) bool {
+		a, _ := strconv.ParseUint(result[i].AppId, 10, 64)
+		b, _ := strconv.ParseUint(result[j].AppId, 10, 64)
+		return a < b
+	})
+
+	return result
+}
+
+// App returns the local configuration of the specified app.
+func (o *LocalConfig) App(appId string) (App, bool) {
+	apps, ok := o.apps(false)
+	if !ok {
+		return App{}, false
+	}
+
+	entry, ok := apps.Get(appId)
+	if !ok || !entry.IsObject {
+		return App{}, false
+	}
+
+	return appFromKeyValue(entry), true
+}
+
+// SetLaunchOptions sets the launch options of the specified app. The
+// launch options are removed if options is empty.
+func (o *LocalConfig) SetLaunchOptions(appId string, options string) error {
+	id, err := strconv.ParseUint(appId, 10, 32)
+	if err != nil || id == 0 {
+		return errors.New("the app ID '" + appId + "' is not a valid app ID")
+	}
+
+	apps, _ := o.apps(true)
+
+	if len(options) == 0 {
+		entry, ok := apps.Get(appId)
+		if ok && entry.IsObject {
+			entry.Remove(launchOptionsKey)
+		}
+
+		return nil
+	}
+
+	apps.SetObject(appId).Set(launchOptionsKey, options)
+
+	return nil
+}
+
+// Write writes the local configuration file to the specified io.Writer.
+func (o *LocalConfig) Write(w io.Writer) error {
+	return vdf.WriteText(w, o.doc)
+}
+
+// apps returns the object containing the per-app configuration. If create
+// is true, the object is created if it does not exist.
+func (o *LocalConfig) apps(create bool) (*vdf.KeyValue, bool) {
+	if !create {
+		return o.doc.Lookup(append([]string{rootKey}, appsKeys...)...)
+	}
+
+	current := o.doc.SetObject(rootKey)
+
+	for _, key := range appsKeys {
+		current = current.SetObject(key)
+	}
+
+	return current, true
+}
+
+func appFromKeyValue(entry *vdf.KeyValue) App {
+	app := App{
+		AppId: entry.Key,
+	}
+
+	app.LastPlayedEpoch = intValue(entry, lastPlayedKey)
+	app.PlaytimeMinutes = intValue(entry, playtimeKey)
+	app.PlaytimeTwoWeeksMinutes = intValue(entry, playtime2WeeksKey)
+	app.LaunchOptions, _ = entry.StringValue(launchOptionsKey)
+	app.CloudSyncState, _ = entry.StringValue(cloudKey, lastSyncStateKey)
+
+	return app
+}
+
+// intValue returns the integer value of the specified key, or 0 if it
+// is missing or malformed.
+func intValue(entry *vdf.KeyValue, key string) int64 {
+	raw, _ := entry.StringValue(key)
+
+	value, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
+
+// Parse parses a local configuration file.
+func Parse(r io.Reader) (*LocalConfig, error) {
+	doc, err := vdf.ParseText(r)
+	if err != nil {
+		return nil, errors.New("failed to parse local configuration file - " + err.Error())
+	}
+
+	root, ok := doc.Get(rootKey)
+	if !ok || !root.IsObject {
+		return nil, errors.New("the local configuration file is missing its '" + rootKey + "' object")
+	}
+
+	return &LocalConfig{
+		doc: doc,
+	}, nil
+}
+
+// Read reads the local configuration file of the specified Steam user.
+func Read(dv locations.DataVerifier, userId string) (*LocalConfig, error) {
+	filePath, err := filePath(dv, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := locations.FileSystemOf(dv).Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// WriteConfig configures the local configuration file write operation.
+type WriteConfig struct {
+	// DataVerifier is used to get the local configuration file path.
+	DataVerifier locations.DataVerifier
+
+	// OwnerUserId is the Steam user ID whose file is written.
+	OwnerUserId string
+
+	// LocalConfig is the local configuration to write.
+	LocalConfig *LocalConfig
+
+	// Mode is the mode to set the file to if a new file is created.
+	// This defaults to 0644 if not specified.
+	Mode os.FileMode
+
+	// RefuseIfSteamRunning specifies whether or not the write should
+	// be refused while Steam is running. Steam saves its in-memory copy
+	// of localconfig.vdf when it exits, which would undo changes such
+	// as new launch options.
+	RefuseIfSteamRunning bool
+}
+
+// Validate returns a non-nil error if the WriteConfig is invalid.
+func (o *WriteConfig) Validate() error {
+	if o.DataVerifier == nil {
+		return errors.New("the DataVerifier cannot be nil")
+	}
+
+	if o.LocalConfig == nil {
+		return errors.New("the LocalConfig cannot be nil")
+	}
+
+	return nil
+}
+
+// Write replaces the local configuration file of the specified Steam user.
+func Write(config WriteConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	filePath, err := filePath(config.DataVerifier, config.OwnerUserId)
+	if err != nil {
+		return err
+	}
+
+	return locations.WriteDataFile(locations.WriteDataFileConfig{
+		DataVerifier:         config.DataVerifier,
+		FilePath:             filePath,
+		Mode:                 config.Mode,
+		RefuseIfSteamRunning: config.RefuseIfSteamRunning,
+		Write:                config.LocalConfig.Write,
+	})
+}
+
+func filePath(dv locations.DataVerifier, userId string) (string, error) {
+	if len(strings.TrimSpace(userId)) == 0 {
+		return "", errors.New("please specify a Steam user ID")
+	}
+
+	accountId, err := naming.ParseUserId(userId)
+	if err != nil {
+		return "", err
+	}
+
+	return locations.LocalConfigFilePath(dv.RootDirPath(), accountId), nil
+}
@@ -0,0 +1,105 @@
+package localconfig
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/internal/steamtest"
+	"github.com/stephen-fox/steamutil/locations"
+)
+
+const (
+	testUserId  = "12345678"
+	testFixture = "localconfig-vdf/localconfig.vdf"
+)
+
+func TestRead(t *testing.T) {
+	dv := steamtest.NewDataVerifier(t, locations.LocalConfigFilePath("", testUserId), testFixture)
+
+	lc, err := Read(dv, "[U:1:"+testUserId+"]")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	apps := lc.Apps()
+
+	if len(apps) != 2 || apps[0].AppId != "220" || apps[1].AppId != "400" {
+		t.Fatal("Unexpected apps -", apps)
+	}
+
+	portal, ok := lc.App("400")
+	if !ok {
+		t.Fatal("App 400 is missing")
+	}
+
+	if portal.PlaytimeMinutes != 1337 || portal.PlaytimeTwoWeeksMinutes != 42 ||
+		portal.LastPlayedEpoch != 1700000000 || portal.CloudSyncState != "synchronized" {
+		t.Fatal("Unexpected app -", portal)
+	}
+
+	if apps[0].LaunchOptions != "-novid -console" {
+		t.Fatal("Unexpected launch options - '" + apps[0].LaunchOptions + "'")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dv := steamtest.NewDataVerifier(t, locations.LocalConfigFilePath("", testUserId), testFixture)
+
+	lc, err := Read(dv, testUserId)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = lc.SetLaunchOptions("400", "%command% -windowed")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = lc.SetLaunchOptions("220", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = lc.SetLaunchOptions("not an app", "-x")
+	if err == nil {
+		t.Fatal("Expected an error for an invalid app ID")
+	}
+
+	err = Write(WriteConfig{
+		DataVerifier: dv,
+		OwnerUserId:  testUserId,
+		LocalConfig:  lc,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	lc, err = Read(dv, testUserId)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	portal, _ := lc.App("400")
+	if portal.LaunchOptions != "%command% -windowed" || portal.PlaytimeMinutes != 1337 {
+		t.Fatal("Unexpected app after write -", portal)
+	}
+
+	hl2, _ := lc.App("220")
+	if len(hl2.LaunchOptions) > 0 {
+		t.Fatal("Launch options were not removed -", hl2)
+	}
+
+	buffer := bytes.NewBuffer(nil)
+
+	err = lc.Write(buffer)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, preserved := range []string{`"FriendStoreLocalPrefs_12345678"		"{\"ePerFriendPrefs\":0}"`, `"lastexit"`, `"Gabe"`} {
+		if !strings.Contains(buffer.String(), preserved) {
+			t.Fatal("Unrelated data was not preserved - " + preserved)
+		}
+	}
+}
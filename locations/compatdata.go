@@ -0,0 +1,241 @@
+package locations
+
+import (
+	"errors"
+	"os"
+	"path"
+)
+
+const (
+	compatDataDirName = "compatdata"
+	prefixDirName     = "pfx"
+)
+
+// CompatData is a compatibility data directory, which Steam creates for
+// each app that is run using a compatibility tool such as Proton. It
+// contains the app's Wine prefix.
+type CompatData struct {
+	// AppId is the ID of the Steam app or non-Steam shortcut that
+	// the directory belongs to.
+	AppId string
+
+	// DirPath is the path to the compatibility data directory.
+	DirPath string
+
+	// LibraryPath is the path to the library containing the directory.
+	LibraryPath string
+}
+
+// PrefixDirPath returns the path to the directory's Wine prefix.
+func (o CompatData) PrefixDirPath() string {
+	return path.Join(o.DirPath, prefixDirName)
+}
+
+// ListCompatData returns the compatibility data directories in the
+// specified libraries, which are usually those returned by Libraries.
+// Libraries without a compatibility data directory are skipped, as are
+// libraries whose directory is the same as that of an earlier library.
+func ListCompatData(dv DataVerifier, libraries []Library) ([]CompatData, error) {
+	fs := FileSystemOf(dv)
+
+	var result []CompatData
+
+	for _, library := range uniqueLibraries(fs, libraries) {
+		infos, err := fs.ReadDir(CompatDataRootDirPath(library.Path))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, info := range infos {
+			if !info.IsDir() || !isPositiveDecimalUint32(info.Name()) {
+				continue
+			}
+
+			result = append(result, CompatData{
+				AppId:       info.Name(),
+				DirPath:     CompatDataDirPath(library.Path, info.Name()),
+				LibraryPath: library.Path,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// FindCompatData returns the compatibility data directory of the specified
+// app ID. The libraries are searched in order. Steam creates the directories
+// of non-Steam shortcuts in the data directory's own library, which is
+// always the first library returned by Libraries.
+//
+// An error satisfying os.IsNotExist is returned if no library contains
+// a compatibility data directory for the app.
+func FindCompatData(dv DataVerifier, libraries []Library, appId string) (CompatData, error) {
+	if !isPositiveDecimalUint32(appId) {
+		return CompatData{}, errors.New("the app ID '" + appId + "' is not a valid app ID")
+	}
+
+	fs := FileSystemOf(dv)
+
+	for _, library := range libraries {
+		dirPath := CompatDataDirPath(library.Path, appId)
+
+		info, err := fs.Stat(dirPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return CompatData{}, err
+		}
+
+		if info.IsDir() {
+			return CompatData{
+				AppId:       appId,
+				DirPath:     dirPath,
+				LibraryPath: library.Path,
+			}, nil
+		}
+	}
+
+	return CompatData{}, &os.PathError{
+		Op:   "find",
+		Path: appId,
+		Err:  os.ErrNotExist,
+	}
+}
+
+// OrphanCompatData returns the compatibility data directories whose app
+// ID is not one of the specified app IDs. The app IDs are usually the
+// IDs of every user's shortcuts and installed apps.
+func OrphanCompatData(compatData []CompatData, knownAppIds []string) []CompatData {
+	known := make(map[string]bool, len(knownAppIds))
+	for _, id := range knownAppIds {
+		known[id] = true
+	}
+
+	var orphans []CompatData
+
+	for _, data := range compatData {
+		if !known[data.AppId] {
+			orphans = append(orphans, data)
+		}
+	}
+
+	return orphans
+}
+
+// DiskUsage returns the total size in bytes of the regular files in the
+// specified directory and its subdirectories. Symbolic links are not
+// followed, as Wine prefixes contain links to directories outside of
+// the prefix.
+func DiskUsage(dv DataVerifier, dirPath string) (int64, error) {
+	fs := FileSystemOf(dv)
+
+	infos, err := fs.ReadDir(dirPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+
+	for _, info := range infos {
+		if info.IsDir() {
+			size, err := DiskUsage(dv, path.Join(dirPath, info.Name()))
+			if err != nil {
+				return 0, err
+			}
+
+			total = total + size
+		} else if info.Mode().IsRegular() {
+			total = total + info.Size()
+		}
+	}
+
+	return total, nil
+}
+
+// RemoveCompatData removes a compatibility data directory and everything
+// that it contains. Symbolic links are removed rather than followed.
+func RemoveCompatData(dv DataVerifier, data CompatData) error {
+	if !isPositiveDecimalUint32(data.AppId) || data.DirPath != CompatDataDirPath(data.LibraryPath, data.AppId) {
+		return errors.New("'" + data.DirPath + "' is not a compatibility data directory")
+	}
+
+	return removeAll(FileSystemOf(dv), data.DirPath)
+}
+
+// MigrateCompatData renames a compatibility data directory so that it
+// belongs to the specified app ID. This is needed when a shortcut's app
+// ID changes because its name or executable path was edited. An error is
+// returned if the app ID already has a compatibility data directory in
+// the same library.
+func MigrateCompatData(dv DataVerifier, data CompatData, newAppId string) (CompatData, error) {
+	if !isPositiveDecimalUint32(newAppId) {
+		return CompatData{}, errors.New("the app ID '" + newAppId + "' is not a valid app ID")
+	}
+
+	fs := FileSystemOf(dv)
+
+	migrated := CompatData{
+		AppId:       newAppId,
+		DirPath:     CompatDataDirPath(data.LibraryPath, newAppId),
+		LibraryPath: data.LibraryPath,
+	}
+
+	_, err := fs.Stat(migrated.DirPath)
+	if err == nil {
+		return CompatData{}, errors.New("a compatibility data directory already exists for app ID '" +
+			newAppId + "' - '" + migrated.DirPath + "'")
+	} else if !os.IsNotExist(err) {
+		return CompatData{}, err
+	}
+
+	err = fs.Rename(data.DirPath, migrated.DirPath)
+	if err != nil {
+		return CompatData{}, err
+	}
+
+	return migrated, nil
+}
+
+// removeAll removes the named file or directory and its contents using
+// the specified FileSystem.
+func removeAll(fs FileSystem, name string) error {
+	infos, err := fs.ReadDir(name)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		childPath := path.Join(name, info.Name())
+
+		if info.IsDir() {
+			err = removeAll(fs, childPath)
+		} else {
+			err = fs.Remove(childPath)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return fs.Remove(name)
+}
+
+// CompatDataRootDirPath generates a path to the directory containing the
+// compatibility data directories of the specified library directory.
+func CompatDataRootDirPath(libraryDirPath string) string {
+	return path.Join(SteamAppsDirPath(libraryDirPath), compatDataDirName)
+}
+
+// CompatDataDirPath generates a path to the compatibility data directory
+// of the specified app ID in the specified library directory. Pass the
+// data directory path as the library directory path to get the directory
+// of a non-Steam shortcut.
+func CompatDataDirPath(libraryDirPath string, appId string) string {
+	return path.Join(CompatDataRootDirPath(libraryDirPath), appId)
+}
@@ -0,0 +1,179 @@
+package locations
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestCompatData(t *testing.T) {
+	dataDirPath := t.TempDir()
+	otherLibraryPath := t.TempDir()
+	outsidePath := t.TempDir()
+
+	dv, err := NewDataVerifierForDir(dataDirPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	libraries := []Library{{Path: dataDirPath}, {Path: otherLibraryPath}}
+
+	outsideFilePath := path.Join(outsidePath, "keep.txt")
+
+	err = ioutil.WriteFile(outsideFilePath, []byte("do not delete"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, data := range []CompatData{
+		{AppId: "3000000000", LibraryPath: dataDirPath},
+		{AppId: "2000000000", LibraryPath: dataDirPath},
+		{AppId: "400", LibraryPath: otherLibraryPath},
+	} {
+		data.DirPath = CompatDataDirPath(data.LibraryPath, data.AppId)
+
+		driveCPath := path.Join(data.PrefixDirPath(), "drive_c")
+
+		err := os.MkdirAll(driveCPath, 0700)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		err = ioutil.WriteFile(path.Join(driveCPath, "save.dat"), make([]byte, 100), 0600)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		err = os.Symlink(outsidePath, path.Join(data.PrefixDirPath(), "outside"))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	err = os.MkdirAll(CompatDataDirPath(dataDirPath, "0"), 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	all, err := ListCompatData(dv, libraries)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(all) != 3 || all[0].AppId != "2000000000" || all[2].LibraryPath != otherLibraryPath {
+		t.Fatal("Unexpected compatibility data directories -", all)
+	}
+
+	size, err := DiskUsage(dv, all[0].DirPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if size != 100 {
+		t.Fatal("Unexpected disk usage -", size)
+	}
+
+	orphans := OrphanCompatData(all, []string{"400", "2000000000"})
+	if len(orphans) != 1 || orphans[0].AppId != "3000000000" {
+		t.Fatal("Unexpected orphans -", orphans)
+	}
+
+	err = RemoveCompatData(dv, orphans[0])
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = os.Stat(orphans[0].DirPath)
+	if !os.IsNotExist(err) {
+		t.Fatal("Compatibility data directory was not removed")
+	}
+
+	_, err = os.Stat(outsideFilePath)
+	if err != nil {
+		t.Fatal("File outside of the prefix was removed - " + err.Error())
+	}
+
+	found, err := FindCompatData(dv, libraries, "400")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if found.LibraryPath != otherLibraryPath {
+		t.Fatal("Unexpected library - '" + found.LibraryPath + "'")
+	}
+
+	_, err = FindCompatData(dv, libraries, "3000000000")
+	if !os.IsNotExist(err) {
+		t.Fatal("Expected a not exist error - got", err)
+	}
+
+	migrated, err := MigrateCompatData(dv, all[0], "2500000000")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = os.Stat(path.Join(migrated.PrefixDirPath(), "drive_c", "save.dat"))
+	if err != nil {
+		t.Fatal("Prefix was not migrated - " + err.Error())
+	}
+
+	_, err = MigrateCompatData(dv, found, "400")
+	if err == nil {
+		t.Fatal("Expected an error when migrating to an existing directory")
+	}
+}
+
+func TestListCompatDataSymlinkedLibrary(t *testing.T) {
+	tempDirPath := t.TempDir()
+
+	dataDirPath := path.Join(tempDirPath, "Steam")
+	linkPath := path.Join(tempDirPath, "root")
+
+	err := os.MkdirAll(CompatDataDirPath(dataDirPath, "400"), 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = os.Symlink(dataDirPath, linkPath)
+	if err != nil {
+		t.Skip("symlinks are not supported - " + err.Error())
+	}
+
+	libraryFolders := "\"libraryfolders\"\n{\n\t\"0\"\n\t{\n\t\t\"path\"\t\t\"" + dataDirPath + "\"\n\t}\n}\n"
+
+	err = ioutil.WriteFile(LibraryFoldersFilePath(dataDirPath), []byte(libraryFolders), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	dv, err := NewDataVerifierForDir(linkPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	libraries, err := Libraries(dv)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(libraries) != 1 {
+		t.Fatal("Unexpected libraries -", libraries)
+	}
+
+	libraries = append(libraries, Library{Path: linkPath})
+
+	all, err := ListCompatData(dv, libraries)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(all) != 1 {
+		t.Fatal("Unexpected compatibility data directories -", all)
+	}
+
+	err = RemoveCompatData(dv, all[0])
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
@@ -0,0 +1,399 @@
+package locations
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	defaultDataFileMode = 0644
+)
+
+var (
+	// ErrReadOnly is returned when a mutating operation is performed
+	// on a FileSystem that does not support it.
+	ErrReadOnly = errors.New("the file system is read-only")
+)
+
+// FileSystem provides access to the files of a Steam data directory.
+// Paths are the paths generated by the DataVerifier that the FileSystem
+// belongs to.
+//
+// Mutating operations return ErrReadOnly if the underlying file system
+// does not support them.
+type FileSystem interface {
+	// Open opens the named file for reading.
+	Open(name string) (fs.File, error)
+
+	// Stat returns information about the named file.
+	Stat(name string) (os.FileInfo, error)
+
+	// ReadDir returns information about the contents of the named
+	// directory, sorted by file name.
+	ReadDir(name string) ([]os.FileInfo, error)
+
+	// ReadFile returns the contents of the named file.
+	ReadFile(name string) ([]byte, error)
+
+	// MkdirAll creates the named directory and any missing parents.
+	MkdirAll(name string, perm os.FileMode) error
+
+	// WriteFile replaces the contents of the named file. Readers of
+	// the file never observe a partially written file.
+	WriteFile(name string, data []byte, perm os.FileMode) error
+
+	// Rename renames a file.
+	Rename(oldName string, newName string) error
+
+	// Remove removes the named file or empty directory.
+	Remove(name string) error
+}
+
+// WritableFS is an fs.FS that supports mutating operations. It can be
+// passed to NewFSDataVerifier in place of a read-only fs.FS.
+type WritableFS interface {
+	fs.FS
+
+	// MkdirAll creates the named directory and any missing parents.
+	MkdirAll(name string, perm fs.FileMode) error
+
+	// WriteFile replaces the contents of the named file.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+
+	// Rename renames a file.
+	Rename(oldName string, newName string) error
+
+	// Remove removes the named file or empty directory.
+	Remove(name string) error
+}
+
+// replaceFile replaces the contents of the named file with the data written
+// by write. The file keeps its permission bits if it already exists, and
+// is created with perm otherwise.
+func replaceFile(fs FileSystem, name string, perm os.FileMode, write func(w io.Writer) error) error {
+	info, err := fs.Stat(name)
+	if err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	buffer := bytes.NewBuffer(nil)
+
+	err = write(buffer)
+	if err != nil {
+		return err
+	}
+
+	return fs.WriteFile(name, buffer.Bytes(), perm)
+}
+
+// WriteDataFileConfig configures the WriteDataFile operation.
+type WriteDataFileConfig struct {
+	// DataVerifier is the DataVerifier whose FileSystem contains
+	// the file.
+	DataVerifier DataVerifier
+
+	// FilePath is the path to the file.
+	FilePath string
+
+	// Mode is the mode to set the file to if a new file is created.
+	// This defaults to defaultDataFileMode if not specified.
+	Mode os.FileMode
+
+	// RefuseIfSteamRunning specifies whether or not CheckSteamNotRunning
+	// should be called before writing the file.
+	RefuseIfSteamRunning bool
+
+	// Write writes the new contents of the file.
+	Write func(w io.Writer) error
+}
+
+// Validate returns a non-nil error if the WriteDataFileConfig is invalid.
+func (o *WriteDataFileConfig) Validate() error {
+	if o.DataVerifier == nil {
+		return errors.New("the DataVerifier cannot be nil")
+	}
+
+	if len(o.FilePath) == 0 {
+		return errors.New("the file path cannot be empty")
+	}
+
+	if o.Write == nil {
+		return errors.New("the write function cannot be nil")
+	}
+
+	if o.Mode == 0 {
+		o.Mode = defaultDataFileMode
+	}
+
+	return nil
+}
+
+// WriteDataFile replaces the contents of a file in a Steam data directory
+// with the data written by the config's Write function. The file keeps its
+// permission bits if it already exists. If RefuseIfSteamRunning is set,
+// the file is left unchanged if CheckSteamNotRunning returns an error.
+func WriteDataFile(config WriteDataFileConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	fs := FileSystemOf(config.DataVerifier)
+
+	if config.RefuseIfSteamRunning {
+		err := CheckSteamNotRunning(fs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return replaceFile(fs, config.FilePath, config.Mode, config.Write)
+}
+
+// OSFileSystem returns a FileSystem that accesses the operating system's
+// file system using native paths.
+func OSFileSystem() FileSystem {
+	return osFileSystem{}
+}
+
+type osFileSystem struct{}
+
+// IsOSFileSystem returns true if the specified FileSystem was returned by
+// OSFileSystem, meaning that its paths are native paths.
+func IsOSFileSystem(fs FileSystem) bool {
+	_, ok := fs.(osFileSystem)
+	return ok
+}
+
+func (o osFileSystem) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (o osFileSystem) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (o osFileSystem) ReadDir(name string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(name)
+}
+
+func (o osFileSystem) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(name)
+}
+
+func (o osFileSystem) MkdirAll(name string, perm os.FileMode) error {
+	return os.MkdirAll(name, perm)
+}
+
+func (o osFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return writeFileAtomic(name, data, perm)
+}
+
+func (o osFileSystem) Rename(oldName string, newName string) error {
+	return os.Rename(oldName, newName)
+}
+
+func (o osFileSystem) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory
+// as filePath and then renames it to filePath.
+func writeFileAtomic(filePath string, data []byte, mode os.FileMode) error {
+	temp, err := ioutil.TempFile(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = temp.Write(data)
+	if err == nil {
+		err = temp.Sync()
+	}
+	if err == nil {
+		err = temp.Chmod(mode)
+	}
+	closeErr := temp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+
+	err = os.Rename(temp.Name(), filePath)
+	if err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+
+	return nil
+}
+
+// NewFileSystem returns a FileSystem backed by an fs.FS. Paths are
+// slash-separated and relative to the root of the fs.FS. Mutating
+// operations are supported if fsys implements WritableFS.
+func NewFileSystem(fsys fs.FS) FileSystem {
+	return &fsFileSystem{
+		fsys: fsys,
+	}
+}
+
+type fsFileSystem struct {
+	fsys fs.FS
+}
+
+func (o *fsFileSystem) Open(name string) (fs.File, error) {
+	return o.fsys.Open(name)
+}
+
+func (o *fsFileSystem) Stat(name string) (os.FileInfo, error) {
+	return fs.Stat(o.fsys, name)
+}
+
+func (o *fsFileSystem) ReadDir(name string) ([]os.FileInfo, error) {
+	entries, err := fs.ReadDir(o.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]os.FileInfo, 0, len(entries))
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i int, j int) bool {
+		return infos[i].Name() < infos[j].Name()
+	})
+
+	return infos, nil
+}
+
+func (o *fsFileSystem) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(o.fsys, name)
+}
+
+func (o *fsFileSystem) MkdirAll(name string, perm os.FileMode) error {
+	w, err := o.writable()
+	if err != nil {
+		return err
+	}
+
+	return w.MkdirAll(name, perm)
+}
+
+func (o *fsFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	w, err := o.writable()
+	if err != nil {
+		return err
+	}
+
+	return w.WriteFile(name, data, perm)
+}
+
+func (o *fsFileSystem) Rename(oldName string, newName string) error {
+	w, err := o.writable()
+	if err != nil {
+		return err
+	}
+
+	return w.Rename(oldName, newName)
+}
+
+func (o *fsFileSystem) Remove(name string) error {
+	w, err := o.writable()
+	if err != nil {
+		return err
+	}
+
+	return w.Remove(name)
+}
+
+func (o *fsFileSystem) writable() (WritableFS, error) {
+	w, ok := o.fsys.(WritableFS)
+	if !ok {
+		return nil, ErrReadOnly
+	}
+
+	return w, nil
+}
+
+// DirFS returns a WritableFS for the tree of files rooted at the specified
+// directory, such as a Steam data directory on a mounted file system.
+func DirFS(dirPath string) WritableFS {
+	return &dirFS{
+		FS:      os.DirFS(dirPath),
+		dirPath: dirPath,
+	}
+}
+
+type dirFS struct {
+	fs.FS
+	dirPath string
+}
+
+func (o *dirFS) MkdirAll(name string, perm fs.FileMode) error {
+	p, err := o.nativePath("mkdir", name)
+	if err != nil {
+		return err
+	}
+
+	return os.MkdirAll(p, perm)
+}
+
+func (o *dirFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	p, err := o.nativePath("write", name)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(p, data, perm)
+}
+
+func (o *dirFS) Rename(oldName string, newName string) error {
+	oldPath, err := o.nativePath("rename", oldName)
+	if err != nil {
+		return err
+	}
+
+	newPath, err := o.nativePath("rename", newName)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(oldPath, newPath)
+}
+
+func (o *dirFS) Remove(name string) error {
+	p, err := o.nativePath("remove", name)
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(p)
+}
+
+func (o *dirFS) nativePath(op string, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{
+			Op:   op,
+			Path: name,
+			Err:  fs.ErrInvalid,
+		}
+	}
+
+	return filepath.Join(o.dirPath, filepath.FromSlash(path.Clean(name))), nil
+}
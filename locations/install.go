@@ -0,0 +1,71 @@
+package locations
+
+import (
+	"errors"
+	"os"
+	"path"
+)
+
+const (
+	// NativeInstall is Steam installed by a distribution package
+	// or Valve's installer.
+	NativeInstall InstallKind = "native"
+
+	// FlatpakInstall is Steam installed as a Flatpak.
+	FlatpakInstall InstallKind = "flatpak"
+
+	// SnapInstall is Steam installed as a Snap.
+	SnapInstall InstallKind = "snap"
+)
+
+const (
+	loginUsersFileName = "loginusers.vdf"
+)
+
+// InstallKind describes how Steam was packaged and installed.
+type InstallKind string
+
+// Install is a Steam installation found on the system.
+type Install struct {
+	// DataDirPath is the path to the install's data directory.
+	DataDirPath string
+
+	// ResolvedDirPath is DataDirPath with any symlinks resolved.
+	ResolvedDirPath string
+
+	// Kind describes how Steam was installed.
+	Kind InstallKind
+}
+
+// PreferredInstall returns the install that was used most recently,
+// based on when a user last logged in. If that cannot be determined,
+// the first install is returned.
+func PreferredInstall(installs []Install) (Install, error) {
+	if len(installs) == 0 {
+		return Install{}, errors.New("no Steam installs were specified")
+	}
+
+	preferred := installs[0]
+	var preferredModTime int64
+
+	for _, install := range installs {
+		info, err := os.Stat(LoginUsersFilePath(install.DataDirPath))
+		if err != nil {
+			continue
+		}
+
+		modTime := info.ModTime().UnixNano()
+		if modTime > preferredModTime {
+			preferred = install
+			preferredModTime = modTime
+		}
+	}
+
+	return preferred, nil
+}
+
+// LoginUsersFilePath generates a path to the file describing the users
+// that have logged in to Steam for the specified data directory.
+func LoginUsersFilePath(dataDirPath string) string {
+	return path.Join(dataDirPath, configDirName, loginUsersFileName)
+}
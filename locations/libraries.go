@@ -0,0 +1,297 @@
+package locations
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+const (
+	steamAppsDirName       = "steamapps"
+	libraryFoldersFileName = "libraryfolders.vdf"
+)
+
+// Library is a Steam library folder, which is a directory that Steam
+// installs games into.
+type Library struct {
+	// Path is the path to the library's directory.
+	Path string
+
+	// Label is the user-specified label of the library, if any.
+	Label string
+
+	// ContentId is Steam's ID for the library's contents, if known.
+	ContentId string
+
+	// TotalSize is the total size of the library's drive in bytes,
+	// if known.
+	TotalSize int64
+
+	// AppIdsToSizes maps the IDs of the apps that the library claims
+	// to contain to their size in bytes. It is nil if the library
+	// folders file does not list the library's apps.
+	AppIdsToSizes map[string]int64
+}
+
+// SteamAppsDirPath returns the path to the library's steamapps
+// directory, which contains the library's app manifests.
+func (o Library) SteamAppsDirPath() string {
+	return SteamAppsDirPath(o.Path)
+}
+
+// Libraries returns the Steam libraries of the DataVerifier's data
+// directory, as described by its library folders file. The data
+// directory's own library is always the first library.
+//
+// The library folders file stores paths of the operating system's file
+// system. If the DataVerifier's FileSystem is a different file system,
+// such as one created by NewFSDataVerifier, paths within the data
+// directory's own library, which Steam lists at index '0', are mapped to
+// paths relative to the DataVerifier's root directory. Libraries outside
+// of it cannot be represented by the FileSystem and are skipped.
+func Libraries(dv DataVerifier) ([]Library, error) {
+	dataDirPath := dv.RootDirPath()
+	fs := FileSystemOf(dv)
+
+	f, err := fs.Open(LibraryFoldersFilePath(dataDirPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Library{{Path: dataDirPath}}, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	libraries, installPath, err := parseLibraryFolders(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsOSFileSystem(fs) {
+		libraries = mapLibraryPaths(libraries, installPath, dataDirPath)
+	}
+
+	return orderLibraries(fs, libraries, dataDirPath), nil
+}
+
+// ParseLibraryFolders parses a library folders file. Both the legacy
+// format, which maps indexes to library paths, and the current format,
+// which maps indexes to objects describing each library, are supported.
+//
+// The legacy format does not list the data directory's own library,
+// so it is added using the specified data directory path.
+func ParseLibraryFolders(r io.Reader, dataDirPath string) ([]Library, error) {
+	libraries, _, err := parseLibraryFolders(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return orderLibraries(OSFileSystem(), libraries, dataDirPath), nil
+}
+
+// parseLibraryFolders parses a library folders file, returning its
+// libraries in the order that they are listed and the path of the
+// library at index '0', which is the library of the Steam install
+// that the file belongs to.
+func parseLibraryFolders(r io.Reader) ([]Library, string, error) {
+	doc, err := vdf.ParseText(r)
+	if err != nil {
+		return nil, "", errors.New("failed to parse library folders file - " + err.Error())
+	}
+
+	root, ok := doc.Get("libraryfolders")
+	if !ok || !root.IsObject {
+		return nil, "", errors.New("the library folders file is missing its 'libraryfolders' object")
+	}
+
+	var libraries []Library
+	var installPath string
+
+	for _, entry := range root.Children {
+		_, err := strconv.Atoi(entry.Key)
+		if err != nil {
+			// Not a library, such as 'contentstatsid'.
+			continue
+		}
+
+		library := Library{
+			Path: entry.Value,
+		}
+
+		if entry.IsObject {
+			library, err = parseLibrary(entry)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+
+		if entry.Key == "0" {
+			installPath = library.Path
+		}
+
+		libraries = append(libraries, library)
+	}
+
+	return libraries, installPath, nil
+}
+
+// orderLibraries removes duplicate libraries and moves the data directory's
+// own library to the front, adding it if it is not listed.
+func orderLibraries(fs FileSystem, libraries []Library, dataDirPath string) []Library {
+	libraries = uniqueLibraries(fs, libraries)
+
+	for i, library := range libraries {
+		if isSameDirPath(fs, library.Path, dataDirPath) {
+			if i > 0 {
+				libraries = append([]Library{library}, append(libraries[:i], libraries[i+1:]...)...)
+			}
+
+			return libraries
+		}
+	}
+
+	return append([]Library{{Path: dataDirPath}}, libraries...)
+}
+
+// mapLibraryPaths maps the paths of libraries in the operating system's
+// file system to paths in a FileSystem whose root directory is the data
+// directory. installPath is the data directory's path in the operating
+// system's file system. Libraries outside of it are skipped.
+func mapLibraryPaths(libraries []Library, installPath string, dataDirPath string) []Library {
+	if len(installPath) == 0 {
+		return nil
+	}
+
+	installPath = hostSlashPath(installPath)
+
+	var mapped []Library
+
+	for _, library := range libraries {
+		libraryPath := hostSlashPath(library.Path)
+
+		if libraryPath == installPath {
+			library.Path = dataDirPath
+		} else if strings.HasPrefix(libraryPath, strings.TrimSuffix(installPath, "/")+"/") {
+			library.Path = path.Join(dataDirPath, strings.TrimPrefix(libraryPath, installPath))
+		} else {
+			continue
+		}
+
+		mapped = append(mapped, library)
+	}
+
+	return mapped
+}
+
+// hostSlashPath returns the cleaned, slash-separated form of a path in
+// the operating system's file system. The path may be a Windows path,
+// regardless of the current operating system.
+func hostSlashPath(p string) string {
+	return path.Clean(strings.ReplaceAll(p, "\\", "/"))
+}
+
+func parseLibrary(entry *vdf.KeyValue) (Library, error) {
+	library := Library{}
+
+	library.Path, _ = entry.StringValue("path")
+	if len(library.Path) == 0 {
+		return library, errors.New("library '" + entry.Key + "' is missing its path")
+	}
+
+	library.Label, _ = entry.StringValue("label")
+	library.ContentId, _ = entry.StringValue("contentid")
+
+	totalSize, ok := entry.StringValue("totalsize")
+	if ok && len(totalSize) > 0 {
+		var err error
+
+		library.TotalSize, err = strconv.ParseInt(totalSize, 10, 64)
+		if err != nil {
+			return library, errors.New("failed to parse total size of library '" +
+				library.Path + "' - " + err.Error())
+		}
+	}
+
+	apps, ok := entry.Get("apps")
+	if ok && apps.IsObject {
+		library.AppIdsToSizes = make(map[string]int64)
+
+		for _, app := range apps.Children {
+			size, err := strconv.ParseInt(app.Value, 10, 64)
+			if err != nil {
+				return library, errors.New("failed to parse size of app '" + app.Key +
+					"' in library '" + library.Path + "' - " + err.Error())
+			}
+
+			library.AppIdsToSizes[app.Key] = size
+		}
+	}
+
+	return library, nil
+}
+
+// uniqueLibraries returns the libraries with any library whose directory
+// is the same as that of an earlier library removed. The same library may
+// be listed more than once, such as by a path and by a symlink to that
+// path.
+func uniqueLibraries(fs FileSystem, libraries []Library) []Library {
+	var unique []Library
+	resolvedPaths := make(map[string]bool)
+
+	for _, library := range libraries {
+		resolved := resolveDirPath(fs, library.Path)
+		if resolvedPaths[resolved] {
+			continue
+		}
+		resolvedPaths[resolved] = true
+
+		unique = append(unique, library)
+	}
+
+	return unique
+}
+
+// isSameDirPath returns true if the specified paths refer to the same
+// directory in the FileSystem. For example, '~/.steam/root' is usually
+// a symlink to '~/.local/share/Steam'.
+func isSameDirPath(fs FileSystem, a string, b string) bool {
+	return resolveDirPath(fs, a) == resolveDirPath(fs, b)
+}
+
+// resolveDirPath returns the cleaned path of a directory in the FileSystem.
+// Symlinks are resolved if the FileSystem is the operating system's file
+// system. The cleaned path is returned if the symlinks cannot be resolved,
+// such as when the directory does not exist.
+func resolveDirPath(fs FileSystem, p string) string {
+	if !IsOSFileSystem(fs) {
+		return path.Clean(p)
+	}
+
+	p = filepath.Clean(filepath.FromSlash(p))
+
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return p
+	}
+
+	return resolved
+}
+
+// LibraryFoldersFilePath generates a path to the library folders file for
+// the specified data directory.
+func LibraryFoldersFilePath(dataDirPath string) string {
+	return path.Join(SteamAppsDirPath(dataDirPath), libraryFoldersFileName)
+}
+
+// SteamAppsDirPath generates a path to the steamapps directory of the
+// specified library directory.
+func SteamAppsDirPath(libraryDirPath string) string {
+	return path.Join(libraryDirPath, steamAppsDirName)
+}
@@ -0,0 +1,192 @@
+package locations
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const (
+	testDataSubDir          = "/.testdata/"
+	libraryFoldersVdfSubDir = testDataSubDir + "libraryfolders-vdf/"
+)
+
+func TestParseLibraryFolders(t *testing.T) {
+	f := openLibraryFoldersTestFile(t, "current.vdf")
+	defer f.Close()
+
+	libraries, err := ParseLibraryFolders(f, "/home/user/.local/share/Steam/")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(libraries) != 2 {
+		t.Fatal("Unexpected number of libraries -", libraries)
+	}
+
+	if libraries[0].ContentId != "1234567890123456789" || libraries[0].AppIdsToSizes["228980"] != 261251940 {
+		t.Fatal("Unexpected main library -", libraries[0])
+	}
+
+	second := libraries[1]
+
+	if second.Path != "/mnt/games/SteamLibrary" || second.Label != "Games" ||
+		second.TotalSize != 1000204886016 || len(second.AppIdsToSizes) != 1 ||
+		second.AppIdsToSizes["400"] != 4418394354 {
+		t.Fatal("Unexpected secondary library -", second)
+	}
+
+	if second.SteamAppsDirPath() != "/mnt/games/SteamLibrary/steamapps" {
+		t.Fatal("Unexpected steamapps directory path - '" + second.SteamAppsDirPath() + "'")
+	}
+}
+
+func TestParseLibraryFoldersLegacy(t *testing.T) {
+	f := openLibraryFoldersTestFile(t, "legacy.vdf")
+	defer f.Close()
+
+	libraries, err := ParseLibraryFolders(f, "/home/user/.steam/steam")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := []string{"/home/user/.steam/steam", "/mnt/games/SteamLibrary", "/media/usb/Steam"}
+
+	if len(libraries) != len(expected) {
+		t.Fatal("Unexpected number of libraries -", libraries)
+	}
+
+	for i, p := range expected {
+		if libraries[i].Path != p {
+			t.Fatal("Unexpected library path - '" + libraries[i].Path + "'")
+		}
+
+		if libraries[i].AppIdsToSizes != nil {
+			t.Fatal("Legacy library should not report apps -", libraries[i].AppIdsToSizes)
+		}
+	}
+}
+
+func TestParseLibraryFoldersSymlinkedDataDir(t *testing.T) {
+	tempDirPath, err := ioutil.TempDir("", "steamutil-libraries-")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(tempDirPath)
+
+	dataDirPath := path.Join(tempDirPath, "Steam")
+
+	err = os.Mkdir(dataDirPath, 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	linkPath := path.Join(tempDirPath, "root")
+
+	err = os.Symlink(dataDirPath, linkPath)
+	if err != nil {
+		t.Skip("symlinks are not supported - " + err.Error())
+	}
+
+	raw := `"libraryfolders"
+{
+	"0"
+	{
+		"path"		"` + dataDirPath + `"
+	}
+	"1"
+	{
+		"path"		"` + linkPath + `"
+	}
+}
+`
+
+	libraries, err := ParseLibraryFolders(strings.NewReader(raw), linkPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(libraries) != 1 || libraries[0].Path != dataDirPath {
+		t.Fatal("Expected a single library - got", libraries)
+	}
+
+	if isSameDirPath(OSFileSystem(), dataDirPath, strings.ToUpper(dataDirPath)) {
+		t.Fatal("Paths that differ in case should not be the same directory")
+	}
+}
+
+func TestLibrariesFSDataVerifier(t *testing.T) {
+	raw := `"libraryfolders"
+{
+	"0"
+	{
+		"path"		"/home/deck/.local/share/Steam"
+		"contentid"		"1234567890123456789"
+	}
+	"1"
+	{
+		"path"		"/run/media/mmcblk0p1/SteamLibrary"
+	}
+	"2"
+	{
+		"path"		"/home/deck/.local/share/Steam/extra"
+	}
+}
+`
+
+	dv, err := NewFSDataVerifier(fstest.MapFS{
+		"steamapps/libraryfolders.vdf":   &fstest.MapFile{Data: []byte(raw)},
+		"steamapps/compatdata/400/pfx":   &fstest.MapFile{Mode: fs.ModeDir},
+		"extra/steamapps/compatdata/220": &fstest.MapFile{Mode: fs.ModeDir},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	libraries, err := Libraries(dv)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(libraries) != 2 || libraries[0].Path != "." || libraries[0].ContentId != "1234567890123456789" ||
+		libraries[1].Path != "extra" {
+		t.Fatal("Unexpected libraries -", libraries)
+	}
+
+	compatData, err := ListCompatData(dv, libraries)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(compatData) != 2 || compatData[0].DirPath != "steamapps/compatdata/400" ||
+		compatData[1].DirPath != "extra/steamapps/compatdata/220" {
+		t.Fatal("Unexpected compatibility data -", compatData)
+	}
+}
+
+func openLibraryFoldersTestFile(t *testing.T, name string) *os.File {
+	p, err := repoPath()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	f, err := os.Open(p + libraryFoldersVdfSubDir + name)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return f
+}
+
+func repoPath() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Dir(wd), nil
+}
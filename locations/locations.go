@@ -2,17 +2,23 @@ package locations
 
 import (
 	"errors"
-	"io/ioutil"
+	"io/fs"
 	"os"
 	"path"
 	"strings"
+
+	"github.com/stephen-fox/steamutil/naming"
 )
 
 const (
-	userDataDirName   = "userdata"
-	userConfigDirName = "config"
-	shortcutsFileName = "shortcuts.vdf"
-	gridDirName       = "grid"
+	userDataDirName     = "userdata"
+	shortcutsFileName   = "shortcuts.vdf"
+	localConfigFileName = "localconfig.vdf"
+	gridDirName         = "grid"
+	configDirName       = "config"
+	configFileName      = "config.vdf"
+	cloudStorageDirName = "cloudstorage"
+	collectionsFileName = "cloud-storage-namespace-1.json"
 )
 
 // DataVerifier gets and verifies file and directory paths to data-related
@@ -25,26 +31,58 @@ type DataVerifier interface {
 	UserDataDirPath() (string, os.FileInfo, error)
 
 	// UserIdsToDataDirPaths returns a map of local Steam user IDs
-	// to their data storage directories.
+	// to their data storage directories. Entries in the user data
+	// directory that are not user IDs, such as '0' or 'anonymous',
+	// are excluded.
 	UserIdsToDataDirPaths() (map[string]string, error)
 
 	// ShortcutsFilePath returns the path to the shortcuts file for a
-	// given Steam user ID.
+	// given Steam user ID. The user ID can be in any of the formats
+	// accepted by naming.ParseSteamID.
 	ShortcutsFilePath(userId string) (string, os.FileInfo, error)
 
 	// GridDirPath returns the path to the grid images directory
-	// for a given Steam user ID.
+	// for a given Steam user ID. The user ID can be in any of the
+	// formats accepted by naming.ParseSteamID.
 	GridDirPath(userId string) (string, os.FileInfo, error)
 }
 
+// FileSystemProvider is implemented by DataVerifiers whose paths do not
+// refer to the operating system's file system, such as those created by
+// NewFSDataVerifier.
+type FileSystemProvider interface {
+	// FileSystem returns the FileSystem that the paths returned by
+	// the DataVerifier refer to.
+	FileSystem() FileSystem
+}
+
+// FileSystemOf returns the FileSystem that the paths returned by the
+// specified DataVerifier refer to. This is the DataVerifier's FileSystem
+// if it implements FileSystemProvider, or the operating system's file
+// system otherwise.
+func FileSystemOf(dv DataVerifier) FileSystem {
+	provider, ok := dv.(FileSystemProvider)
+	if ok {
+		return provider.FileSystem()
+	}
+
+	return OSFileSystem()
+}
+
 type defaultDataVerifier struct {
 	dataDir string
+	fs      FileSystem
 }
 
 func (o defaultDataVerifier) ShortcutsFilePath(userId string) (string, os.FileInfo, error) {
-	filePath := ShortcutsFilePath(o.dataDir, userId)
+	accountId, err := naming.ParseUserId(userId)
+	if err != nil {
+		return "", nil, err
+	}
+
+	filePath := ShortcutsFilePath(o.dataDir, accountId)
 
-	i, err := os.Stat(filePath)
+	i, err := o.fs.Stat(filePath)
 	if err != nil {
 		return "", nil, err
 	}
@@ -60,12 +98,16 @@ func (o defaultDataVerifier) UserIdsToDataDirPaths() (map[string]string, error)
 		return idsToDirs, err
 	}
 
-	infos, err := ioutil.ReadDir(dir)
+	infos, err := o.fs.ReadDir(dir)
 	if err != nil {
 		return idsToDirs, err
 	}
 
 	for _, in := range infos {
+		if !in.IsDir() || !isPositiveDecimalUint32(in.Name()) {
+			continue
+		}
+
 		idsToDirs[in.Name()] = UserIdDirPath(o.dataDir, in.Name())
 	}
 
@@ -75,7 +117,7 @@ func (o defaultDataVerifier) UserIdsToDataDirPaths() (map[string]string, error)
 func (o defaultDataVerifier) UserDataDirPath() (string, os.FileInfo, error) {
 	dirPath := UserDataDirPath(o.dataDir)
 
-	i, err := os.Stat(dirPath)
+	i, err := o.fs.Stat(dirPath)
 	if err != nil {
 		return "", nil, err
 	}
@@ -87,10 +129,19 @@ func (o defaultDataVerifier) RootDirPath() string {
 	return o.dataDir
 }
 
+func (o defaultDataVerifier) FileSystem() FileSystem {
+	return o.fs
+}
+
 func (o defaultDataVerifier) GridDirPath(userId string) (string, os.FileInfo, error) {
-	dirPath := GridDirPath(o.dataDir, userId)
+	accountId, err := naming.ParseUserId(userId)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dirPath := GridDirPath(o.dataDir, accountId)
 
-	i, err := os.Stat(dirPath)
+	i, err := o.fs.Stat(dirPath)
 	if err != nil {
 		return "", nil, err
 	}
@@ -101,13 +152,33 @@ func (o defaultDataVerifier) GridDirPath(userId string) (string, os.FileInfo, er
 // GridDirPath generates a path to the grid images directory for the specified
 // data directory and Steam user ID.
 func GridDirPath(dataDirPath string, userId string) string {
-	return path.Join(dataDirPath, userDataDirName, userId, userConfigDirName, gridDirName)
+	return path.Join(dataDirPath, userDataDirName, userId, configDirName, gridDirName)
 }
 
 // ShortcutsFilePath generates a path to the shortcuts file for the specified
 // data directory and Steam user ID.
 func ShortcutsFilePath(dataDirPath string, userId string) string {
-	return path.Join(dataDirPath, userDataDirName, userId, userConfigDirName, shortcutsFileName)
+	return path.Join(dataDirPath, userDataDirName, userId, configDirName, shortcutsFileName)
+}
+
+// LocalConfigFilePath generates a path to the local configuration file for
+// the specified data directory and Steam user ID.
+func LocalConfigFilePath(dataDirPath string, userId string) string {
+	return path.Join(dataDirPath, userDataDirName, userId, configDirName, localConfigFileName)
+}
+
+// CollectionsFilePath generates a path to the cloud storage file that
+// contains the collections of the specified data directory and Steam
+// user ID.
+func CollectionsFilePath(dataDirPath string, userId string) string {
+	return path.Join(dataDirPath, userDataDirName, userId, configDirName,
+		cloudStorageDirName, collectionsFileName)
+}
+
+// ConfigFilePath generates a path to Steam's global configuration file for
+// the specified data directory.
+func ConfigFilePath(dataDirPath string) string {
+	return path.Join(dataDirPath, configDirName, configFileName)
 }
 
 // UserIdDirPath generates a path to the specified Steam user
@@ -132,9 +203,59 @@ func NewDataVerifier() (DataVerifier, error) {
 
 	return &defaultDataVerifier{
 		dataDir: dirPath,
+		fs:      OSFileSystem(),
 	}, nil
 }
 
+// NewDataVerifierForDir creates a DataVerifier for the Steam data directory
+// at the specified path, rather than the data directory of the local
+// Steam installation.
+func NewDataVerifierForDir(dataDirPath string) (DataVerifier, error) {
+	dv := &defaultDataVerifier{
+		dataDir: dataDirPath,
+		fs:      OSFileSystem(),
+	}
+
+	err := dv.verifyRoot()
+	if err != nil {
+		return &defaultDataVerifier{}, err
+	}
+
+	return dv, nil
+}
+
+// NewFSDataVerifier creates a DataVerifier for a Steam data directory that
+// is the root of the specified fs.FS. The paths returned by the DataVerifier
+// are relative to the root of the fs.FS, and must be accessed using its
+// FileSystem. Mutating operations are supported if fsys implements
+// WritableFS.
+func NewFSDataVerifier(fsys fs.FS) (DataVerifier, error) {
+	dv := &defaultDataVerifier{
+		dataDir: ".",
+		fs:      NewFileSystem(fsys),
+	}
+
+	err := dv.verifyRoot()
+	if err != nil {
+		return &defaultDataVerifier{}, err
+	}
+
+	return dv, nil
+}
+
+func (o defaultDataVerifier) verifyRoot() error {
+	i, err := o.fs.Stat(o.dataDir)
+	if err != nil {
+		return err
+	}
+
+	if !i.IsDir() {
+		return errors.New("the Steam data directory path is not a directory - '" + o.dataDir + "'")
+	}
+
+	return nil
+}
+
 // IsInstalled returns true if Steam is installed.
 func IsInstalled() bool {
 	_, _, err := DataDirPath()
@@ -3,6 +3,7 @@ package locations
 import (
 	"os"
 	"path"
+	"path/filepath"
 )
 
 // DataDirPath returns the path to Steam's data directory.
@@ -21,3 +22,25 @@ func DataDirPath() (string, os.FileInfo, error) {
 
 	return dirPath, i, nil
 }
+
+// Installs returns every Steam install found on the system. A non-nil
+// error is returned if no installs are found.
+func Installs() ([]Install, error) {
+	dirPath, _, err := DataDirPath()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := filepath.EvalSymlinks(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Install{
+		{
+			DataDirPath:     dirPath,
+			ResolvedDirPath: resolved,
+			Kind:            NativeInstall,
+		},
+	}, nil
+}
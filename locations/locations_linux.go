@@ -3,21 +3,110 @@ package locations
 import (
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 )
 
-// DataDirPath returns the path to Steam's data directory.
+const (
+	flatpakAppId = "com.valvesoftware.Steam"
+)
+
+// DataDirPath returns the path to Steam's data directory. If Steam is
+// installed more than once, the path of the preferred install is
+// returned.
 func DataDirPath() (string, os.FileInfo, error) {
-	homePath, err := homePath()
+	installs, err := Installs()
 	if err != nil {
 		return "", nil, err
 	}
 
-	dirPath := path.Join(homePath, ".steam", "root")
+	install, err := PreferredInstall(installs)
+	if err != nil {
+		return "", nil, err
+	}
 
-	i, err := os.Stat(dirPath)
+	i, err := os.Stat(install.DataDirPath)
 	if err != nil {
 		return "", nil, err
 	}
 
-	return dirPath, i, nil
+	return install.DataDirPath, i, nil
+}
+
+// Installs returns every Steam install found on the system, including
+// Flatpak and Snap installs. Installs are de-duplicated by resolving
+// symlinks. A non-nil error is returned if no installs are found.
+func Installs() ([]Install, error) {
+	homePath, err := homePath()
+	if err != nil {
+		return nil, err
+	}
+
+	var installs []Install
+	resolvedPaths := make(map[string]bool)
+
+	for _, candidate := range installCandidates(homePath) {
+		resolved, err := filepath.EvalSymlinks(candidate.DataDirPath)
+		if err != nil {
+			continue
+		}
+
+		info, err := os.Stat(resolved)
+		if err != nil || !info.IsDir() || resolvedPaths[resolved] {
+			continue
+		}
+
+		resolvedPaths[resolved] = true
+
+		candidate.ResolvedDirPath = resolved
+		candidate.Kind = installKind(resolved, candidate.Kind)
+
+		installs = append(installs, candidate)
+	}
+
+	if len(installs) == 0 {
+		return nil, &os.PathError{
+			Op:   "stat",
+			Path: path.Join(homePath, ".steam", "root"),
+			Err:  os.ErrNotExist,
+		}
+	}
+
+	return installs, nil
+}
+
+// installCandidates returns the known Steam data directory locations,
+// in order of preference.
+func installCandidates(homePath string) []Install {
+	candidates := []Install{
+		{DataDirPath: path.Join(homePath, ".steam", "root"), Kind: NativeInstall},
+		{DataDirPath: path.Join(homePath, ".steam", "steam"), Kind: NativeInstall},
+	}
+
+	xdgDataHome := os.Getenv("XDG_DATA_HOME")
+	if len(strings.TrimSpace(xdgDataHome)) > 0 {
+		candidates = append(candidates, Install{
+			DataDirPath: path.Join(xdgDataHome, "Steam"),
+			Kind:        NativeInstall,
+		})
+	}
+
+	return append(candidates,
+		Install{DataDirPath: path.Join(homePath, ".local", "share", "Steam"), Kind: NativeInstall},
+		Install{DataDirPath: path.Join(homePath, ".var", "app", flatpakAppId, ".local", "share", "Steam"), Kind: FlatpakInstall},
+		Install{DataDirPath: path.Join(homePath, ".var", "app", flatpakAppId, "data", "Steam"), Kind: FlatpakInstall},
+		Install{DataDirPath: path.Join(homePath, "snap", "steam", "common", ".local", "share", "Steam"), Kind: SnapInstall})
+}
+
+// installKind determines the kind of install from its resolved path,
+// which catches symlinks that point into a sandboxed install.
+func installKind(resolvedPath string, fallback InstallKind) InstallKind {
+	switch {
+	case strings.Contains(resolvedPath, "/.var/app/"+flatpakAppId+"/"):
+		return FlatpakInstall
+	case strings.Contains(resolvedPath, "/snap/steam/"):
+		return SnapInstall
+	}
+
+	return fallback
 }
@@ -0,0 +1,114 @@
+package locations
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestInstalls(t *testing.T) {
+	homePath, err := ioutil.TempDir("", "steamutil-locations-test")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(homePath)
+
+	setTestEnv(t, "HOME", homePath)
+	setTestEnv(t, "XDG_DATA_HOME", path.Join(homePath, "xdg"))
+
+	flatpakPath := path.Join(homePath, ".var", "app", flatpakAppId, ".local", "share", "Steam")
+	snapPath := path.Join(homePath, "snap", "steam", "common", ".local", "share", "Steam")
+
+	for _, p := range []string{path.Join(flatpakPath, "config"), path.Join(snapPath, "config"), path.Join(homePath, ".steam")} {
+		err := os.MkdirAll(p, 0700)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	err = os.Symlink(flatpakPath, path.Join(homePath, ".steam", "root"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	installs, err := Installs()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(installs) != 2 {
+		t.Fatal("Unexpected number of installs -", installs)
+	}
+
+	if installs[0].DataDirPath != path.Join(homePath, ".steam", "root") ||
+		installs[0].ResolvedDirPath != flatpakPath || installs[0].Kind != FlatpakInstall {
+		t.Fatal("Unexpected first install -", installs[0])
+	}
+
+	if installs[1].DataDirPath != snapPath || installs[1].Kind != SnapInstall {
+		t.Fatal("Unexpected second install -", installs[1])
+	}
+
+	preferred, err := PreferredInstall(installs)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if preferred != installs[0] {
+		t.Fatal("Unexpected preferred install without login history -", preferred)
+	}
+
+	for i, install := range installs {
+		p := LoginUsersFilePath(install.DataDirPath)
+
+		err := ioutil.WriteFile(p, nil, 0600)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		modTime := time.Now().Add(time.Duration(i) * time.Hour)
+
+		err = os.Chtimes(p, modTime, modTime)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	preferred, err = PreferredInstall(installs)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if preferred != installs[1] {
+		t.Fatal("Unexpected preferred install -", preferred)
+	}
+
+	dataDirPath, _, err := DataDirPath()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if dataDirPath != snapPath {
+		t.Fatal("Unexpected data directory path - '" + dataDirPath + "'")
+	}
+}
+
+// setTestEnv sets an environment variable for the duration of a test.
+func setTestEnv(t *testing.T, key string, value string) {
+	previous, wasSet := os.LookupEnv(key)
+
+	err := os.Setenv(key, value)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	t.Cleanup(func() {
+		if wasSet {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
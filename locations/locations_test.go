@@ -1,14 +1,25 @@
 package locations
 
 import (
-	"io/ioutil"
+	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path"
 	"testing"
+	"testing/fstest"
+)
+
+const (
+	steamDataDirSubDir = testDataSubDir + "steam-data-dir"
+	fixtureUserId      = "12345678"
 )
 
 func TestDataDirPath(t *testing.T) {
+	if !IsInstalled() {
+		t.Skip()
+	}
+
 	p, i, err := DataDirPath()
 	if err != nil {
 		t.Fatal(err.Error())
@@ -20,118 +31,240 @@ func TestDataDirPath(t *testing.T) {
 }
 
 func TestDefaultDataVerifier_DataDirPath(t *testing.T) {
-	v, err := NewDataVerifier()
+	for name, v := range testDataVerifiers(t) {
+		_, err := FileSystemOf(v).Stat(v.RootDirPath())
+		if err != nil {
+			t.Fatal(name + " - " + err.Error())
+		}
+	}
+}
+
+func TestDefaultDataVerifier_UserDataDirPath(t *testing.T) {
+	for name, v := range testDataVerifiers(t) {
+		p, i, err := v.UserDataDirPath()
+		if err != nil {
+			t.Fatal(name + " - " + err.Error())
+		} else if i == nil {
+			t.Fatal(name + " - info is nil")
+		}
+
+		log.Println(p)
+	}
+}
+
+func TestDefaultDataVerifier_UserIdsToDataDirPaths(t *testing.T) {
+	for name, v := range testDataVerifiers(t) {
+		m, err := v.UserIdsToDataDirPaths()
+		if err != nil {
+			t.Fatal(name + " - " + err.Error())
+		}
+
+		if name != "installed" && m[fixtureUserId] != UserIdDirPath(v.RootDirPath(), fixtureUserId) {
+			t.Fatal(name+" - unexpected user IDs -", m)
+		}
+
+		log.Println(m)
+	}
+}
+
+func TestDefaultDataVerifier_ShortcutsFilePath(t *testing.T) {
+	for name, v := range testDataVerifiers(t) {
+		p, i, err := v.ShortcutsFilePath(getSomeUserId(t, v))
+		if err != nil {
+			t.Fatal(name + " - " + err.Error())
+		} else if i == nil {
+			t.Fatal(name + " - info is nil")
+		}
+
+		if path.Base(p) != shortcutsFileName {
+			t.Fatal("File name should be", shortcutsFileName)
+		}
+
+		raw, err := FileSystemOf(v).ReadFile(p)
+		if err != nil {
+			t.Fatal(name + " - " + err.Error())
+		}
+
+		if len(raw) == 0 {
+			t.Fatal(name + " - shortcuts file is empty")
+		}
+
+		log.Println(p)
+	}
+}
+
+func TestDefaultDataVerifier_GridDirPath(t *testing.T) {
+	for name, v := range testDataVerifiers(t) {
+		_, _, err := v.GridDirPath(getSomeUserId(t, v))
+		if err != nil {
+			t.Fatal(name + " - " + err.Error())
+		}
+	}
+}
+
+func TestNewFSDataVerifier_ReadOnly(t *testing.T) {
+	v, err := NewFSDataVerifier(fstest.MapFS{
+		"userdata/" + fixtureUserId + "/config/grid": &fstest.MapFile{Mode: fs.ModeDir},
+	})
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	_, err = os.Stat(v.RootDirPath())
+	gridDirPath, _, err := v.GridDirPath(fixtureUserId)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
-}
 
-func TestDefaultDataVerifier_UserDataDirPath(t *testing.T) {
-	if !IsInstalled() {
-		t.Skip()
+	if gridDirPath != "userdata/"+fixtureUserId+"/config/grid" {
+		t.Fatal("Unexpected grid directory path - '" + gridDirPath + "'")
+	}
+
+	err = FileSystemOf(v).WriteFile(path.Join(gridDirPath, "400.png"), nil, 0644)
+	if err != ErrReadOnly {
+		t.Fatal("Expected a read-only error - got", err)
 	}
+}
+
+func TestDirFS(t *testing.T) {
+	dirPath := t.TempDir()
 
-	v, err := NewDataVerifier()
+	v, err := NewFSDataVerifier(DirFS(dirPath))
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	p, i, err := v.UserDataDirPath()
+	gridDirPath := GridDirPath(v.RootDirPath(), fixtureUserId)
+
+	err = FileSystemOf(v).MkdirAll(gridDirPath, 0755)
 	if err != nil {
 		t.Fatal(err.Error())
-	} else if i == nil {
-		t.Fatal("Info is nil")
 	}
 
-	log.Println(p)
-}
+	filePath := path.Join(gridDirPath, "400.png")
 
-func TestDefaultDataVerifier_UserIdsToDataDirPaths(t *testing.T) {
-	if !IsInstalled() {
-		t.Skip()
+	err = FileSystemOf(v).WriteFile(filePath, []byte("image"), 0644)
+	if err != nil {
+		t.Fatal(err.Error())
 	}
 
-	v, err := NewDataVerifier()
+	raw, err := os.ReadFile(path.Join(dirPath, filePath))
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	m, err := v.UserIdsToDataDirPaths()
+	if string(raw) != "image" {
+		t.Fatal("Unexpected file contents - '" + string(raw) + "'")
+	}
+
+	err = FileSystemOf(v).Remove("../outside")
+	if err == nil {
+		t.Fatal("Expected an error for a path outside of the file system")
+	}
+}
+
+func TestWriteDataFile(t *testing.T) {
+	dirPath := t.TempDir()
+
+	v, err := NewDataVerifierForDir(dirPath)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	log.Println(m)
-}
+	filePath := ConfigFilePath(v.RootDirPath())
 
-func TestDefaultDataVerifier_ShortcutsFilePath(t *testing.T) {
-	if !IsInstalled() {
-		t.Skip()
+	err = os.MkdirAll(path.Dir(filePath), 0700)
+	if err != nil {
+		t.Fatal(err.Error())
 	}
 
-	v, err := NewDataVerifier()
+	err = os.WriteFile(filePath, []byte("old"), 0600)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	p, i, err := v.ShortcutsFilePath(getSomeUserId(v))
+	err = WriteDataFile(WriteDataFileConfig{
+		DataVerifier: v,
+		FilePath:     filePath,
+		Write: func(w io.Writer) error {
+			_, err := w.Write([]byte("new"))
+			return err
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	info, err := os.Stat(filePath)
 	if err != nil {
 		t.Fatal(err.Error())
-		return
-	} else if i == nil {
-		t.Fatal("Info is nil")
 	}
 
-	if path.Base(p) != shortcutsFileName {
-		t.Fatal("File name should be", shortcutsFileName)
+	if info.Mode().Perm() != 0600 {
+		t.Fatal("The file's mode was not preserved - got", info.Mode().Perm())
 	}
 
-	log.Println(p)
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if string(raw) != "new" {
+		t.Fatal("Unexpected file contents - '" + string(raw) + "'")
+	}
 }
 
-func TestDefaultDataVerifier_GridDirPath(t *testing.T) {
-	if !IsInstalled() {
-		t.Skip()
+// testDataVerifiers returns DataVerifiers for the fixture Steam data
+// directory, plus one for the local Steam installation if present.
+func testDataVerifiers(t *testing.T) map[string]DataVerifier {
+	p, err := repoPath()
+	if err != nil {
+		t.Fatal(err.Error())
 	}
 
-	v, err := NewDataVerifier()
+	fixturePath := p + steamDataDirSubDir
+
+	dirVerifier, err := NewDataVerifierForDir(fixturePath)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	_, _, err = v.GridDirPath(getSomeUserId(v))
+	fsVerifier, err := NewFSDataVerifier(os.DirFS(fixturePath))
 	if err != nil {
 		t.Fatal(err.Error())
 	}
-}
 
-func getSomeUserId(dv DataVerifier) string {
-	if !IsInstalled() {
-		panic("Steam must be installed to get a user ID")
+	verifiers := map[string]DataVerifier{
+		"dir": dirVerifier,
+		"fs":  fsVerifier,
 	}
 
-	userDirPath, _, err := dv.UserDataDirPath()
-	if err != nil {
-		panic(err.Error())
+	if IsInstalled() {
+		installed, err := NewDataVerifier()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		verifiers["installed"] = installed
 	}
 
-	infos, err := ioutil.ReadDir(userDirPath)
+	return verifiers
+}
+
+func getSomeUserId(t *testing.T, dv DataVerifier) string {
+	idsToDirPaths, err := dv.UserIdsToDataDirPaths()
 	if err != nil {
-		panic(err.Error())
+		t.Fatal(err.Error())
 	}
 
-	for _, info := range infos {
-		if info.IsDir() {
-			return info.Name()
-		}
+	if _, ok := idsToDirPaths[fixtureUserId]; ok {
+		return fixtureUserId
+	}
+
+	for id := range idsToDirPaths {
+		return id
 	}
 
-	panic("No user IDs were found")
+	t.Fatal("No user IDs were found")
 
 	return ""
 }
@@ -61,3 +61,25 @@ func bitsToDriveLetters(bitMap uint32) []string {
 
 	return drives
 }
+
+// Installs returns every Steam install found on the system. A non-nil
+// error is returned if no installs are found.
+func Installs() ([]Install, error) {
+	dirPath, _, err := DataDirPath()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := filepath.EvalSymlinks(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Install{
+		{
+			DataDirPath:     dirPath,
+			ResolvedDirPath: resolved,
+			Kind:            NativeInstall,
+		},
+	}, nil
+}
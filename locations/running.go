@@ -0,0 +1,51 @@
+package locations
+
+import (
+	"errors"
+)
+
+var (
+	// ErrSteamRunning is returned by operations that refuse to modify
+	// user data while Steam is running. Steam overwrites files such as
+	// shortcuts.vdf with its in-memory copy when it exits, which would
+	// discard the changes.
+	ErrSteamRunning = errors.New("steam is running - changes to its files would be lost when it exits")
+
+	// ErrRunningDetectionUnsupported is returned by IsSteamRunning on
+	// platforms where it cannot detect a running Steam client.
+	ErrRunningDetectionUnsupported = errors.New("detecting whether Steam is running is not supported on this platform")
+)
+
+// CheckSteamNotRunning returns ErrSteamRunning if Steam is running and
+// the specified FileSystem is the operating system's file system. Writers
+// with a RefuseIfSteamRunning option call it with the FileSystem that they
+// write to before writing, and leave their file unchanged if it returns
+// an error.
+//
+// The check is skipped for other FileSystems, such as those created by
+// NewFileSystem. Their files belong to a Steam data directory that is not
+// used by the Steam client running on this system, such as a mounted
+// backup, so the host's Steam client cannot overwrite them.
+//
+// Steam is assumed not to be running on platforms where IsSteamRunning
+// returns ErrRunningDetectionUnsupported, so the check never prevents
+// writes on those platforms.
+func CheckSteamNotRunning(fs FileSystem) error {
+	if !IsOSFileSystem(fs) {
+		return nil
+	}
+
+	running, err := IsSteamRunning()
+	if err == ErrRunningDetectionUnsupported {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if running {
+		return ErrSteamRunning
+	}
+
+	return nil
+}
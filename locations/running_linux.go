@@ -0,0 +1,127 @@
+package locations
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+const (
+	procDirPath      = "/proc"
+	pidFileName      = "steam.pid"
+	registryFileName = "registry.vdf"
+)
+
+// IsSteamRunning returns true if a Steam client is running. The process
+// IDs recorded in Steam's pid file and registry file are checked against
+// the running processes, so stale files left behind by a crash are
+// ignored.
+func IsSteamRunning() (bool, error) {
+	homePath, err := homePath()
+	if err != nil {
+		return false, err
+	}
+
+	return isSteamRunning(homePath, procDirPath)
+}
+
+func isSteamRunning(homePath string, procDirPath string) (bool, error) {
+	for _, steamDirPath := range steamDotDirPaths(homePath) {
+		pids, err := recordedSteamPids(steamDirPath)
+		if err != nil {
+			return false, err
+		}
+
+		for _, pid := range pids {
+			if isSteamProcess(procDirPath, pid) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// steamDotDirPaths returns the possible locations of the '.steam'
+// directory, which contains Steam's pid and registry files.
+func steamDotDirPaths(homePath string) []string {
+	return []string{
+		path.Join(homePath, ".steam"),
+		path.Join(homePath, ".var", "app", flatpakAppId, ".steam"),
+		path.Join(homePath, "snap", "steam", "common", ".steam"),
+	}
+}
+
+// recordedSteamPids returns the process IDs recorded in the pid file and
+// registry file in the specified '.steam' directory.
+func recordedSteamPids(steamDirPath string) ([]int, error) {
+	var pids []int
+
+	raw, err := ioutil.ReadFile(path.Join(steamDirPath, pidFileName))
+	if err == nil {
+		pid, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+		if err == nil && pid > 0 {
+			pids = append(pids, pid)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	f, err := os.Open(path.Join(steamDirPath, registryFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return pids, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	registry, err := vdf.ParseText(f)
+	if err != nil {
+		// Steam may be in the middle of writing the file.
+		return pids, nil
+	}
+
+	steam, ok := registry.Lookup("Registry", "HKCU", "Software", "Valve", "Steam")
+	if !ok {
+		return pids, nil
+	}
+
+	for _, keys := range [][]string{{"ActiveProcess", "pid"}, {"Running", "pid"}} {
+		value, ok := steam.StringValue(keys...)
+		if !ok {
+			continue
+		}
+
+		pid, err := strconv.ParseInt(strings.TrimSpace(value), 0, 64)
+		if err == nil && pid > 0 {
+			pids = append(pids, int(pid))
+		}
+	}
+
+	return pids, nil
+}
+
+// isSteamProcess returns true if the process with the specified ID is
+// running and is a Steam client.
+func isSteamProcess(procDirPath string, pid int) bool {
+	raw, err := ioutil.ReadFile(path.Join(procDirPath, strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return false
+	}
+
+	args := bytes.Split(raw, []byte{0})
+	if len(args) == 0 {
+		return false
+	}
+
+	name := path.Base(string(args[0]))
+
+	return name == "steam" || name == "steam.sh"
+}
@@ -0,0 +1,85 @@
+package locations
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestIsSteamRunning(t *testing.T) {
+	homePath := t.TempDir()
+	procPath := t.TempDir()
+
+	steamDirPath := path.Join(homePath, ".steam")
+
+	err := os.MkdirAll(steamDirPath, 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	running, err := isSteamRunning(homePath, procPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if running {
+		t.Fatal("Steam should not be running without a pid file")
+	}
+
+	writeTestFile(t, path.Join(steamDirPath, pidFileName), "1234\n")
+	writeTestFile(t, path.Join(procPath, "1234", "cmdline"), "/usr/bin/vim\x00steam.pid\x00")
+
+	running, err = isSteamRunning(homePath, procPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if running {
+		t.Fatal("A stale pid file that refers to another process was trusted")
+	}
+
+	writeTestFile(t, path.Join(steamDirPath, registryFileName), `"Registry"
+{
+	"HKCU"
+	{
+		"Software"
+		{
+			"Valve"
+			{
+				"Steam"
+				{
+					"ActiveProcess"
+					{
+						"pid"		"0x1a0a"
+					}
+				}
+			}
+		}
+	}
+}
+`)
+	writeTestFile(t, path.Join(procPath, "6666", "cmdline"),
+		"/home/user/.local/share/Steam/ubuntu12_32/steam\x00-srt-logger-opened\x00")
+
+	running, err = isSteamRunning(homePath, procPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !running {
+		t.Fatal("Steam should be running according to the registry file")
+	}
+}
+
+func writeTestFile(t *testing.T, filePath string, contents string) {
+	err := os.MkdirAll(path.Dir(filePath), 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = ioutil.WriteFile(filePath, []byte(contents), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
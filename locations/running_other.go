@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package locations
+
+// IsSteamRunning returns true if a Steam client is running. Detection is
+// currently only supported on Linux - ErrRunningDetectionUnsupported is
+// returned on other platforms.
+func IsSteamRunning() (bool, error) {
+	return false, ErrRunningDetectionUnsupported
+}
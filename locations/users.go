@@ -0,0 +1,188 @@
+package locations
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/stephen-fox/steamutil/naming"
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+// User is a Steam user that has logged in to Steam on this system, or
+// that has a user data directory.
+type User struct {
+	// AccountId is the user's 32-bit account ID. The user's data
+	// directory is named after it.
+	AccountId string
+
+	// SteamId is the user's 64-bit Steam ID.
+	SteamId naming.SteamID
+
+	// AccountName is the name that the user logs in with.
+	AccountName string
+
+	// PersonaName is the user's display name.
+	PersonaName string
+
+	// MostRecent is true if the user was the most recent user to
+	// log in to Steam.
+	MostRecent bool
+
+	// RememberPassword is true if Steam remembers the user's
+	// password.
+	RememberPassword bool
+
+	// LastLoginEpoch is the time that the user last logged in, in
+	// seconds since the Unix epoch.
+	LastLoginEpoch int64
+
+	// DataDirPath is the path to the user's data directory. It is
+	// empty if the user does not have a data directory.
+	DataDirPath string
+}
+
+// Users returns the Steam users that have logged in to Steam according to
+// its login users file, as well as the users that have a data directory.
+// The users are sorted by account ID.
+func Users(dv DataVerifier) ([]User, error) {
+	var loginUsers []User
+
+	f, err := FileSystemOf(dv).Open(LoginUsersFilePath(dv.RootDirPath()))
+	if err == nil {
+		loginUsers, err = ParseLoginUsers(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	idsToDirPaths, err := dv.UserIdsToDataDirPaths()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var users []User
+
+	for _, user := range loginUsers {
+		user.DataDirPath = idsToDirPaths[user.AccountId]
+		delete(idsToDirPaths, user.AccountId)
+
+		users = append(users, user)
+	}
+
+	for accountId, dirPath := range idsToDirPaths {
+		id, _ := strconv.ParseUint(accountId, 10, 32)
+
+		users = append(users, User{
+			AccountId:   accountId,
+			SteamId:     naming.NewIndividualSteamID(uint32(id)),
+			DataDirPath: dirPath,
+		})
+	}
+
+	sort.Slice(users, func(i int, j int) bool {
+		a, _ := strconv.ParseUint(users[i].AccountId, 10, 32)
+		b, _ := strconv.ParseUint(users[j].AccountId, 10, 32)
+		return a < b
+	})
+
+	return users, nil
+}
+
+// MostRecentUser returns the user that most recently logged in to Steam.
+func MostRecentUser(dv DataVerifier) (User, error) {
+	users, err := Users(dv)
+	if err != nil {
+		return User{}, err
+	}
+
+	var mostRecent *User
+
+	for i := range users {
+		if users[i].MostRecent {
+			return users[i], nil
+		}
+
+		if users[i].LastLoginEpoch > 0 && (mostRecent == nil || users[i].LastLoginEpoch > mostRecent.LastLoginEpoch) {
+			mostRecent = &users[i]
+		}
+	}
+
+	if mostRecent == nil {
+		return User{}, errors.New("no Steam user has logged in")
+	}
+
+	return *mostRecent, nil
+}
+
+// ParseLoginUsers parses a login users file. The users' DataDirPath
+// fields are not set.
+func ParseLoginUsers(r io.Reader) ([]User, error) {
+	doc, err := vdf.ParseText(r)
+	if err != nil {
+		return nil, errors.New("failed to parse login users file - " + err.Error())
+	}
+
+	root, ok := doc.Get("users")
+	if !ok || !root.IsObject {
+		return nil, errors.New("the login users file is missing its 'users' object")
+	}
+
+	var users []User
+
+	for _, entry := range root.Children {
+		if !entry.IsObject {
+			continue
+		}
+
+		steamId, err := naming.ParseSteamID(entry.Key)
+		if err != nil {
+			return nil, errors.New("the login users file contains an invalid Steam ID - " + err.Error())
+		}
+
+		user := User{
+			AccountId: steamId.AccountIdString(),
+			SteamId:   steamId,
+		}
+
+		user.AccountName, _ = entry.StringValue("AccountName")
+		user.PersonaName, _ = entry.StringValue("PersonaName")
+
+		mostRecent, _ := entry.StringValue("MostRecent")
+		user.MostRecent = mostRecent == "1"
+
+		rememberPassword, _ := entry.StringValue("RememberPassword")
+		user.RememberPassword = rememberPassword == "1"
+
+		timestamp, ok := entry.StringValue("Timestamp")
+		if ok && len(timestamp) > 0 {
+			user.LastLoginEpoch, err = strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				return nil, errors.New("failed to parse login timestamp of user '" +
+					entry.Key + "' - " + err.Error())
+			}
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// isPositiveDecimalUint32 returns true if the specified name is the
+// canonical decimal form of a non-zero 32-bit integer. Account IDs and
+// app IDs are such integers, so this distinguishes them from directories
+// such as '0' or 'anonymous'.
+func isPositiveDecimalUint32(name string) bool {
+	id, err := strconv.ParseUint(name, 10, 32)
+	if err != nil {
+		return false
+	}
+
+	return id > 0 && strconv.FormatUint(id, 10) == name
+}
@@ -0,0 +1,64 @@
+package locations
+
+import (
+	"testing"
+)
+
+func TestUsers(t *testing.T) {
+	for name, v := range testDataVerifiers(t) {
+		if name == "installed" {
+			continue
+		}
+
+		users, err := Users(v)
+		if err != nil {
+			t.Fatal(name + " - " + err.Error())
+		}
+
+		if len(users) != 3 {
+			t.Fatal(name+" - unexpected users -", users)
+		}
+
+		dirOnly := users[0]
+		if dirOnly.AccountId != "11111111" || dirOnly.SteamId.String() != "76561197971376839" ||
+			dirOnly.DataDirPath != UserIdDirPath(v.RootDirPath(), "11111111") {
+			t.Fatal(name+" - unexpected user -", dirOnly)
+		}
+
+		recent := users[1]
+		if recent.AccountId != fixtureUserId || recent.AccountName != "chess_player" ||
+			recent.PersonaName != "Chess Player" || !recent.MostRecent || !recent.RememberPassword ||
+			recent.LastLoginEpoch != 1700000000 || len(recent.DataDirPath) == 0 {
+			t.Fatal(name+" - unexpected user -", recent)
+		}
+
+		loginOnly := users[2]
+		if loginOnly.AccountId != "87654321" || loginOnly.SteamId.String() != "76561198047920049" ||
+			len(loginOnly.DataDirPath) > 0 {
+			t.Fatal(name+" - unexpected user -", loginOnly)
+		}
+
+		mostRecent, err := MostRecentUser(v)
+		if err != nil {
+			t.Fatal(name + " - " + err.Error())
+		}
+
+		if mostRecent.AccountId != fixtureUserId {
+			t.Fatal(name+" - unexpected most recent user -", mostRecent)
+		}
+	}
+}
+
+func TestIsPositiveDecimalUint32(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"12345678":   true,
+		"0":          false,
+		"anonymous":  false,
+		"0123":       false,
+		"4294967296": false,
+	} {
+		if isPositiveDecimalUint32(name) != expected {
+			t.Fatal("Unexpected result for '" + name + "'")
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package naming
 
 import (
+	"errors"
 	"hash/crc32"
 	"strconv"
 )
@@ -16,3 +17,27 @@ func LegacyNonSteamGameId(gameName string, executablePath string) string {
 	top := uint64(crc32.ChecksumIEEE([]byte(uniqueName)) | 0x80000000)
 	return strconv.FormatUint(top<<32|0x02000000, 10)
 }
+
+// NonSteamAppId returns the 32-bit app ID for a non-Steam game. Current
+// versions of Steam use this ID for the names of grid images.
+func NonSteamAppId(gameName string, executablePath string) string {
+	uniqueName := executablePath + gameName
+	appId := crc32.ChecksumIEEE([]byte(uniqueName)) | 0x80000000
+	return strconv.FormatUint(uint64(appId), 10)
+}
+
+// LegacyIdToAppId converts a legacy 64-bit non-Steam game ID (as generated
+// by LegacyNonSteamGameId) to the equivalent 32-bit app ID (as generated
+// by NonSteamAppId).
+func LegacyIdToAppId(legacyId string) (string, error) {
+	id, err := strconv.ParseUint(legacyId, 10, 64)
+	if err != nil {
+		return "", errors.New("failed to parse legacy ID '" + legacyId + "' - " + err.Error())
+	}
+
+	if id&0xffffffff != 0x02000000 || id>>63 != 1 {
+		return "", errors.New("'" + legacyId + "' is not a legacy non-Steam game ID")
+	}
+
+	return strconv.FormatUint(id>>32, 10), nil
+}
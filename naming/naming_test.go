@@ -10,3 +10,29 @@ func TestLegacyNonSteamGameId(t *testing.T) {
 		t.Fatal("Did not get expected value of '" + expected + "' - got '" + name + "'")
 	}
 }
+
+func TestNonSteamAppId(t *testing.T) {
+	name := NonSteamAppId("Pikmin", `"D:\Program Files\Dolphin\Dolphin.exe"`)
+
+	expected := "2624352236"
+	if name != expected {
+		t.Fatal("Did not get expected value of '" + expected + "' - got '" + name + "'")
+	}
+}
+
+func TestLegacyIdToAppId(t *testing.T) {
+	appId, err := LegacyIdToAppId(LegacyNonSteamGameId("Pikmin", `"D:\Program Files\Dolphin\Dolphin.exe"`))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := NonSteamAppId("Pikmin", `"D:\Program Files\Dolphin\Dolphin.exe"`)
+	if appId != expected {
+		t.Fatal("Did not get expected value of '" + expected + "' - got '" + appId + "'")
+	}
+
+	_, err = LegacyIdToAppId("400")
+	if err == nil {
+		t.Fatal("Converting a Steam app ID did not produce an error")
+	}
+}
@@ -0,0 +1,327 @@
+package naming
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	// InvalidUniverse is the universe of an invalid SteamID.
+	InvalidUniverse Universe = 0
+
+	// PublicUniverse is the universe of every regular Steam account.
+	PublicUniverse Universe = 1
+
+	// BetaUniverse is Valve's beta testing universe.
+	BetaUniverse Universe = 2
+
+	// InternalUniverse is Valve's internal universe.
+	InternalUniverse Universe = 3
+
+	// DevUniverse is Valve's development universe.
+	DevUniverse Universe = 4
+)
+
+const (
+	// InvalidAccount is the type of an invalid SteamID.
+	InvalidAccount AccountType = 0
+
+	// IndividualAccount is the type of a regular user account.
+	IndividualAccount AccountType = 1
+
+	// MultiseatAccount is the type of a multiseat account, such as
+	// one used by a cyber cafe.
+	MultiseatAccount AccountType = 2
+
+	// GameServerAccount is the type of a persistent game server
+	// account.
+	GameServerAccount AccountType = 3
+
+	// AnonGameServerAccount is the type of an anonymous game server
+	// account.
+	AnonGameServerAccount AccountType = 4
+
+	// PendingAccount is the type of an account that is still being
+	// created.
+	PendingAccount AccountType = 5
+
+	// ContentServerAccount is the type of a content server account.
+	ContentServerAccount AccountType = 6
+
+	// ClanAccount is the type of a Steam group.
+	ClanAccount AccountType = 7
+
+	// ChatAccount is the type of a group chat or lobby.
+	ChatAccount AccountType = 8
+
+	// ConsoleUserAccount is the type of a fake account used for a
+	// console user, such as a PlayStation Network user.
+	ConsoleUserAccount AccountType = 9
+
+	// AnonUserAccount is the type of an anonymous user account.
+	AnonUserAccount AccountType = 10
+)
+
+const (
+	// DesktopInstance is the instance of an individual account that
+	// is logged in to a desktop Steam client.
+	DesktopInstance uint32 = 1
+
+	accountIdBits   = 32
+	instanceBits    = 20
+	accountTypeBits = 4
+
+	instanceMask    = 1<<instanceBits - 1
+	accountTypeMask = 1<<accountTypeBits - 1
+)
+
+// steam3Letters maps account types to the letters used for them in the
+// '[U:1:N]' format.
+var steam3Letters = map[AccountType]string{
+	InvalidAccount:        "I",
+	IndividualAccount:     "U",
+	MultiseatAccount:      "M",
+	GameServerAccount:     "G",
+	AnonGameServerAccount: "A",
+	PendingAccount:        "P",
+	ContentServerAccount:  "C",
+	ClanAccount:           "g",
+	ChatAccount:           "T",
+	AnonUserAccount:       "a",
+}
+
+// Universe is the Steam universe that an account belongs to.
+type Universe uint8
+
+// AccountType is the type of a Steam account.
+type AccountType uint8
+
+// SteamID is a 64-bit Steam ID. It is made up of an account's universe,
+// type, instance and 32-bit account ID.
+type SteamID uint64
+
+// NewSteamID creates a SteamID from its parts.
+func NewSteamID(universe Universe, accountType AccountType, instance uint32, accountId uint32) SteamID {
+	return SteamID(uint64(universe)<<(accountIdBits+instanceBits+accountTypeBits) |
+		uint64(accountType&accountTypeMask)<<(accountIdBits+instanceBits) |
+		uint64(instance&instanceMask)<<accountIdBits |
+		uint64(accountId))
+}
+
+// NewIndividualSteamID creates the SteamID of a regular user account in
+// the public universe.
+func NewIndividualSteamID(accountId uint32) SteamID {
+	return NewSteamID(PublicUniverse, IndividualAccount, DesktopInstance, accountId)
+}
+
+// ParseSteamID parses a Steam ID in any of the following formats:
+//
+//   - SteamID64, such as '76561197972611406'
+//   - 32-bit account ID, such as '12345678' (assumed to be an individual
+//     account in the public universe)
+//   - Steam2, such as 'STEAM_0:0:6172839'
+//   - Steam3, such as '[U:1:12345678]'
+func ParseSteamID(s string) (SteamID, error) {
+	s = strings.TrimSpace(s)
+
+	var id SteamID
+	var err error
+
+	switch {
+	case strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]"):
+		id, err = parseSteam3(s[1 : len(s)-1])
+	case strings.HasPrefix(strings.ToUpper(s), "STEAM_"):
+		id, err = parseSteam2(s[len("STEAM_"):])
+	default:
+		id, err = parseNumericSteamId(s)
+	}
+	if err != nil {
+		return 0, errors.New("'" + s + "' is not a valid Steam ID - " + err.Error())
+	}
+
+	if id.AccountId() == 0 && id.AccountType() != AnonGameServerAccount && id.AccountType() != AnonUserAccount {
+		return 0, errors.New("'" + s + "' is not a valid Steam ID - the account ID cannot be 0")
+	}
+
+	return id, nil
+}
+
+func parseNumericSteamId(s string) (SteamID, error) {
+	value, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, errors.New("expected a numeric ID")
+	}
+
+	if value <= 1<<accountIdBits-1 {
+		return NewIndividualSteamID(uint32(value)), nil
+	}
+
+	id := SteamID(value)
+
+	if id.Universe() == InvalidUniverse || id.Universe() > DevUniverse {
+		return 0, errors.New("the universe is invalid")
+	}
+
+	if id.AccountType() == InvalidAccount || id.AccountType() > AnonUserAccount {
+		return 0, errors.New("the account type is invalid")
+	}
+
+	return id, nil
+}
+
+func parseSteam2(s string) (SteamID, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, errors.New("expected 'STEAM_X:Y:Z'")
+	}
+
+	universe, err := strconv.ParseUint(parts[0], 10, 8)
+	if err != nil || universe > uint64(DevUniverse) {
+		return 0, errors.New("the universe is invalid")
+	}
+
+	// Older games format the public universe as 0.
+	if universe == 0 {
+		universe = uint64(PublicUniverse)
+	}
+
+	y, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil || y > 1 {
+		return 0, errors.New("the Y component must be 0 or 1")
+	}
+
+	z, err := strconv.ParseUint(parts[2], 10, 31)
+	if err != nil {
+		return 0, errors.New("the Z component is invalid")
+	}
+
+	return NewSteamID(Universe(universe), IndividualAccount, DesktopInstance, uint32(z<<1|y)), nil
+}
+
+func parseSteam3(s string) (SteamID, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 && len(parts) != 4 {
+		return 0, errors.New("expected '[X:U:N]'")
+	}
+
+	accountType := InvalidAccount
+	found := false
+
+	for t, letter := range steam3Letters {
+		if letter == parts[0] {
+			accountType = t
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return 0, errors.New("the account type letter '" + parts[0] + "' is invalid")
+	}
+
+	universe, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil || universe == 0 || universe > uint64(DevUniverse) {
+		return 0, errors.New("the universe is invalid")
+	}
+
+	accountId, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return 0, errors.New("the account ID is invalid")
+	}
+
+	instance := uint64(0)
+	if accountType == IndividualAccount {
+		instance = uint64(DesktopInstance)
+	}
+
+	if len(parts) == 4 {
+		instance, err = strconv.ParseUint(parts[3], 10, instanceBits)
+		if err != nil {
+			return 0, errors.New("the instance is invalid")
+		}
+	}
+
+	return NewSteamID(Universe(universe), accountType, uint32(instance), uint32(accountId)), nil
+}
+
+// AccountId returns the 32-bit account ID. Steam names each user's data
+// directory after their account ID.
+func (o SteamID) AccountId() uint32 {
+	return uint32(o)
+}
+
+// AccountIdString returns the 32-bit account ID as a string.
+func (o SteamID) AccountIdString() string {
+	return strconv.FormatUint(uint64(o.AccountId()), 10)
+}
+
+// Instance returns the account instance.
+func (o SteamID) Instance() uint32 {
+	return uint32(o>>accountIdBits) & instanceMask
+}
+
+// AccountType returns the account type.
+func (o SteamID) AccountType() AccountType {
+	return AccountType(o>>(accountIdBits+instanceBits)) & accountTypeMask
+}
+
+// Universe returns the universe that the account belongs to.
+func (o SteamID) Universe() Universe {
+	return Universe(o >> (accountIdBits + instanceBits + accountTypeBits))
+}
+
+// IsIndividual returns true if the ID belongs to a regular user account.
+func (o SteamID) IsIndividual() bool {
+	return o.AccountType() == IndividualAccount
+}
+
+// String returns the SteamID64 form of the ID.
+func (o SteamID) String() string {
+	return strconv.FormatUint(uint64(o), 10)
+}
+
+// Steam2 returns the 'STEAM_X:Y:Z' form of the ID. The public universe
+// is formatted as 0, as most games do.
+func (o SteamID) Steam2() string {
+	universe := o.Universe()
+	if universe == PublicUniverse {
+		universe = 0
+	}
+
+	return "STEAM_" + strconv.Itoa(int(universe)) + ":" +
+		strconv.FormatUint(uint64(o.AccountId()&1), 10) + ":" +
+		strconv.FormatUint(uint64(o.AccountId()>>1), 10)
+}
+
+// Steam3 returns the '[U:1:N]' form of the ID.
+func (o SteamID) Steam3() string {
+	letter, ok := steam3Letters[o.AccountType()]
+	if !ok {
+		letter = "i"
+	}
+
+	s := "[" + letter + ":" + strconv.Itoa(int(o.Universe())) + ":" + o.AccountIdString()
+
+	if o.AccountType() == AnonGameServerAccount || o.AccountType() == MultiseatAccount {
+		s = s + ":" + strconv.FormatUint(uint64(o.Instance()), 10)
+	}
+
+	return s + "]"
+}
+
+// ParseUserId parses a Steam ID in any of the formats accepted by
+// ParseSteamID and returns the account ID of the user that it belongs to.
+// An error is returned if the ID does not belong to an individual account.
+func ParseUserId(s string) (string, error) {
+	id, err := ParseSteamID(s)
+	if err != nil {
+		return "", err
+	}
+
+	if !id.IsIndividual() {
+		return "", errors.New("'" + s + "' is not the Steam ID of a user")
+	}
+
+	return id.AccountIdString(), nil
+}
@@ -0,0 +1,69 @@
+package naming
+
+import (
+	"testing"
+)
+
+func TestParseSteamID(t *testing.T) {
+	const expected = "76561197972611406"
+
+	for _, s := range []string{"76561197972611406", "12345678", "STEAM_0:0:6172839", "STEAM_1:0:6172839", "[U:1:12345678]", " [U:1:12345678:1] "} {
+		id, err := ParseSteamID(s)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if id.String() != expected {
+			t.Fatal("Unexpected SteamID64 for '" + s + "' - " + id.String())
+		}
+	}
+
+	id := NewIndividualSteamID(12345678)
+
+	if id.AccountId() != 12345678 || id.AccountIdString() != "12345678" || id.Universe() != PublicUniverse ||
+		id.AccountType() != IndividualAccount || id.Instance() != DesktopInstance || !id.IsIndividual() {
+		t.Fatal("Unexpected parts -", id.AccountId(), id.Universe(), id.AccountType(), id.Instance())
+	}
+
+	if id.Steam2() != "STEAM_0:0:6172839" {
+		t.Fatal("Unexpected Steam2 form - " + id.Steam2())
+	}
+
+	if id.Steam3() != "[U:1:12345678]" {
+		t.Fatal("Unexpected Steam3 form - " + id.Steam3())
+	}
+
+	server, err := ParseSteamID("[A:1:0:1234]")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if server.AccountType() != AnonGameServerAccount || server.Instance() != 1234 || server.Steam3() != "[A:1:0:1234]" {
+		t.Fatal("Unexpected anonymous game server ID - " + server.Steam3())
+	}
+}
+
+func TestParseSteamID_Invalid(t *testing.T) {
+	for _, s := range []string{"", "0", "anonymous", "STEAM_0:2:1", "STEAM_0:0", "[X:1:5]", "[U:0:5]", "[U:1:-5]", "18446744073709551615"} {
+		_, err := ParseSteamID(s)
+		if err == nil {
+			t.Fatal("Expected an error for '" + s + "'")
+		}
+	}
+}
+
+func TestParseUserId(t *testing.T) {
+	accountId, err := ParseUserId("[U:1:12345678]")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if accountId != "12345678" {
+		t.Fatal("Unexpected account ID - " + accountId)
+	}
+
+	_, err = ParseUserId("[g:1:4]")
+	if err == nil {
+		t.Fatal("Expected an error for a clan ID")
+	}
+}
@@ -0,0 +1,384 @@
+package shortcuts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+const (
+	// EntryAdded means that the shortcut is only in the new list.
+	EntryAdded DiffKind = "added"
+
+	// EntryRemoved means that the shortcut is only in the old list.
+	EntryRemoved DiffKind = "removed"
+
+	// EntryModified means that the shortcut is in both lists, but one
+	// or more of its fields differ.
+	EntryModified DiffKind = "modified"
+)
+
+const (
+	// appIdField is the field that newer versions of Steam use to store
+	// a shortcut's app ID.
+	appIdField = "appid"
+)
+
+// FieldChange is a difference between a field of two Shortcuts.
+type FieldChange struct {
+	// Field is the name of the Shortcut field, such as 'AppName'. If
+	// IsUnknownField is true, it is the name of the field in the
+	// shortcuts file instead.
+	Field string `json:"field"`
+
+	// Old is the field's value in the old Shortcut. It is nil if an
+	// unknown field was added.
+	Old interface{} `json:"old"`
+
+	// New is the field's value in the new Shortcut. It is nil if an
+	// unknown field was removed.
+	New interface{} `json:"new"`
+
+	// IsUnknownField is true if the field is one of the Shortcut's
+	// UnknownFields.
+	IsUnknownField bool `json:"unknown_field"`
+}
+
+// FieldChanges returns the fields that differ between two Shortcuts. The
+// Id field is ignored, as it is the position of the Shortcut in its file
+// rather than a property of the Shortcut.
+func FieldChanges(old Shortcut, new Shortcut) []FieldChange {
+	var changes []FieldChange
+
+	add := func(field string, o interface{}, n interface{}, changed bool) {
+		if changed {
+			changes = append(changes, FieldChange{
+				Field: field,
+				Old:   o,
+				New:   n,
+			})
+		}
+	}
+
+	add("AppName", old.AppName, new.AppName, old.AppName != new.AppName)
+	add("ExePath", old.ExePath, new.ExePath, old.ExePath != new.ExePath)
+	add("StartDir", old.StartDir, new.StartDir, old.StartDir != new.StartDir)
+	add("IconPath", old.IconPath, new.IconPath, old.IconPath != new.IconPath)
+	add("ShortcutPath", old.ShortcutPath, new.ShortcutPath, old.ShortcutPath != new.ShortcutPath)
+	add("LaunchOptions", old.LaunchOptions, new.LaunchOptions, old.LaunchOptions != new.LaunchOptions)
+	add("IsHidden", old.IsHidden, new.IsHidden, old.IsHidden != new.IsHidden)
+	add("AllowDesktopConfig", old.AllowDesktopConfig, new.AllowDesktopConfig,
+		old.AllowDesktopConfig != new.AllowDesktopConfig)
+	add("AllowOverlay", old.AllowOverlay, new.AllowOverlay, old.AllowOverlay != new.AllowOverlay)
+	add("IsOpenVr", old.IsOpenVr, new.IsOpenVr, old.IsOpenVr != new.IsOpenVr)
+	add("LastPlayTimeEpoch", old.LastPlayTimeEpoch, new.LastPlayTimeEpoch,
+		old.LastPlayTimeEpoch != new.LastPlayTimeEpoch)
+	add("Tags", old.Tags, new.Tags, !stringsEqual(old.Tags, new.Tags))
+
+	return append(changes, unknownFieldChanges(old, new)...)
+}
+
+// unknownFieldChanges returns the differences between the UnknownFields
+// of two Shortcuts. Fields are matched by name.
+func unknownFieldChanges(old Shortcut, new Shortcut) []FieldChange {
+	var changes []FieldChange
+
+	newFields := make(map[string]vdf.Field)
+	for _, f := range new.UnknownFields {
+		newFields[f.Name()] = f
+	}
+
+	oldFields := make(map[string]bool)
+
+	for _, o := range old.UnknownFields {
+		oldFields[o.Name()] = true
+
+		change := FieldChange{
+			Field:          o.Name(),
+			Old:            o.UntypedValue(),
+			IsUnknownField: true,
+		}
+
+		n, ok := newFields[o.Name()]
+		if ok {
+			change.New = n.UntypedValue()
+
+			if o.ValueType() == n.ValueType() && fmt.Sprint(change.Old) == fmt.Sprint(change.New) {
+				continue
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	for _, n := range new.UnknownFields {
+		if !oldFields[n.Name()] {
+			changes = append(changes, FieldChange{
+				Field:          n.Name(),
+				New:            n.UntypedValue(),
+				IsUnknownField: true,
+			})
+		}
+	}
+
+	return changes
+}
+
+// DiffKind describes how a shortcut differs between two lists.
+type DiffKind string
+
+// EntryDiff is the difference between a shortcut in two lists.
+type EntryDiff struct {
+	// Kind describes how the shortcut differs.
+	Kind DiffKind
+
+	// Old is the shortcut in the old list. It is the zero value if
+	// the shortcut was added.
+	Old Shortcut
+
+	// New is the shortcut in the new list. It is the zero value if
+	// the shortcut was removed.
+	New Shortcut
+
+	// Fields are the fields that differ. The fields of an added or
+	// removed shortcut are compared to those of an empty Shortcut.
+	Fields []FieldChange
+}
+
+// Shortcut returns the new shortcut, or the old shortcut if it was
+// removed.
+func (o EntryDiff) Shortcut() Shortcut {
+	if o.Kind == EntryRemoved {
+		return o.Old
+	}
+
+	return o.New
+}
+
+// DiffResult is the difference between two lists of shortcuts.
+type DiffResult struct {
+	// Entries are the shortcuts that differ. Removed shortcuts come
+	// first, in the order of the old list, followed by added and
+	// modified shortcuts, in the order of the new list.
+	Entries []EntryDiff
+}
+
+// IsEmpty returns true if the lists of shortcuts are the same.
+func (o DiffResult) IsEmpty() bool {
+	return len(o.Entries) == 0
+}
+
+// String returns a human-readable description of the differences.
+func (o DiffResult) String() string {
+	if o.IsEmpty() {
+		return "no changes"
+	}
+
+	sb := &strings.Builder{}
+
+	for _, entry := range o.Entries {
+		s := entry.Shortcut()
+
+		switch entry.Kind {
+		case EntryAdded:
+			sb.WriteString("+ ")
+		case EntryRemoved:
+			sb.WriteString("- ")
+		default:
+			sb.WriteString("~ ")
+		}
+
+		sb.WriteString(string(entry.Kind) + " " + strconv.Quote(s.AppName) + " (app ID " + steamAppId(s) + ")\n")
+
+		if entry.Kind != EntryModified {
+			continue
+		}
+
+		for _, field := range entry.Fields {
+			sb.WriteString("    " + field.Field + ": " + formatValue(field.Old) + " -> " + formatValue(field.New) + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// MarshalJSON encodes the differences as a JSON object.
+func (o DiffResult) MarshalJSON() ([]byte, error) {
+	type jsonEntry struct {
+		Kind    DiffKind      `json:"kind"`
+		AppId   string        `json:"app_id"`
+		AppName string        `json:"app_name"`
+		Fields  []FieldChange `json:"fields"`
+	}
+
+	entries := make([]jsonEntry, 0, len(o.Entries))
+
+	for _, entry := range o.Entries {
+		s := entry.Shortcut()
+
+		fields := entry.Fields
+		if fields == nil {
+			fields = []FieldChange{}
+		}
+
+		entries = append(entries, jsonEntry{
+			Kind:    entry.Kind,
+			AppId:   steamAppId(s),
+			AppName: s.AppName,
+			Fields:  fields,
+		})
+	}
+
+	return json.Marshal(struct {
+		Entries []jsonEntry `json:"entries"`
+	}{
+		Entries: entries,
+	})
+}
+
+// Diff returns the differences between two lists of shortcuts, such as
+// the shortcuts in a file before and after a sync.
+//
+// Shortcuts are paired by the app ID that newer versions of Steam store
+// in the shortcuts file. Shortcuts without a stored app ID are paired by
+// the app ID generated from their executable path and name. A shortcut
+// whose executable path or name changed, and that has no stored app ID,
+// is reported as removed and added.
+func Diff(old []Shortcut, new []Shortcut) DiffResult {
+	matched := make(map[int]int)
+	oldMatched := make(map[int]bool)
+
+	pair := func(key func(s Shortcut) (string, bool)) {
+		oldKeys := make(map[string]int)
+
+		for i, s := range old {
+			k, ok := key(s)
+			if !ok || oldMatched[i] {
+				continue
+			}
+
+			_, exists := oldKeys[k]
+			if !exists {
+				oldKeys[k] = i
+			}
+		}
+
+		for j, s := range new {
+			_, done := matched[j]
+			if done {
+				continue
+			}
+
+			k, ok := key(s)
+			if !ok {
+				continue
+			}
+
+			i, ok := oldKeys[k]
+			if ok && !oldMatched[i] {
+				matched[j] = i
+				oldMatched[i] = true
+			}
+		}
+	}
+
+	pair(storedAppId)
+	pair(func(s Shortcut) (string, bool) {
+		return s.AppId(), true
+	})
+
+	var result DiffResult
+
+	for i, s := range old {
+		if !oldMatched[i] {
+			result.Entries = append(result.Entries, EntryDiff{
+				Kind:   EntryRemoved,
+				Old:    s,
+				Fields: FieldChanges(s, Shortcut{}),
+			})
+		}
+	}
+
+	for j, s := range new {
+		i, ok := matched[j]
+		if !ok {
+			result.Entries = append(result.Entries, EntryDiff{
+				Kind:   EntryAdded,
+				New:    s,
+				Fields: FieldChanges(Shortcut{}, s),
+			})
+			continue
+		}
+
+		fields := FieldChanges(old[i], s)
+		if len(fields) > 0 {
+			result.Entries = append(result.Entries, EntryDiff{
+				Kind:   EntryModified,
+				Old:    old[i],
+				New:    s,
+				Fields: fields,
+			})
+		}
+	}
+
+	return result
+}
+
+// storedAppId returns the app ID that newer versions of Steam store in
+// the shortcut's entry, if any.
+func storedAppId(s Shortcut) (string, bool) {
+	for _, f := range s.UnknownFields {
+		if f.Name() == appIdField && f.Int32Value() != 0 {
+			return strconv.FormatUint(uint64(uint32(f.Int32Value())), 10), true
+		}
+	}
+
+	return "", false
+}
+
+// steamAppId returns the app ID that Steam uses for the shortcut. This
+// is the stored app ID if there is one, and the app ID generated from the
+// shortcut's executable path and name otherwise.
+func steamAppId(s Shortcut) string {
+	appId, ok := storedAppId(s)
+	if !ok {
+		return s.AppId()
+	}
+
+	return appId
+}
+
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		return strconv.Quote(value)
+	case []string:
+		quoted := make([]string, len(value))
+		for i := range value {
+			quoted[i] = strconv.Quote(value[i])
+		}
+
+		return "[" + strings.Join(quoted, ", ") + "]"
+	}
+
+	return fmt.Sprint(v)
+}
+
+func stringsEqual(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,157 @@
+package shortcuts
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+func TestFieldChanges(t *testing.T) {
+	old := Shortcut{
+		Id:      1,
+		AppName: "woah",
+		ExePath: "/path/to/something",
+		Tags:    []string{"cool"},
+	}
+
+	new := old
+	new.Id = 2
+	new.LaunchOptions = "-windowed"
+	new.Tags = []string{"cool", "story"}
+
+	changes := FieldChanges(old, new)
+
+	if len(changes) != 2 || changes[0].Field != "LaunchOptions" || changes[0].New != "-windowed" ||
+		changes[1].Field != "Tags" {
+		t.Fatal("Unexpected changes -", changes)
+	}
+
+	if len(FieldChanges(old, old)) != 0 {
+		t.Fatal("Expected no changes for identical shortcuts")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	chess := Shortcut{
+		AppName: "Chess",
+		ExePath: "/Applications/Chess.app",
+		Tags:    []string{"board"},
+	}
+
+	pikmin := Shortcut{
+		Id:      1,
+		AppName: "Pikmin",
+		ExePath: "/games/pikmin.exe",
+		UnknownFields: []vdf.Field{
+			vdf.NewInt32Field("appid", -1234567),
+			vdf.NewStringField("FlatpakAppID", ""),
+		},
+	}
+
+	gone := Shortcut{
+		Id:      2,
+		AppName: "Gone",
+		ExePath: "/games/gone.exe",
+	}
+
+	renamedPikmin := pikmin
+	renamedPikmin.Id = 0
+	renamedPikmin.AppName = "Pikmin 2"
+	renamedPikmin.UnknownFields = []vdf.Field{
+		vdf.NewInt32Field("appid", -1234567),
+		vdf.NewStringField("FlatpakAppID", "org.DolphinEmu.dolphin-emu"),
+	}
+
+	changedChess := chess
+	changedChess.Id = 1
+	changedChess.Tags = []string{"board", "classic"}
+
+	added := Shortcut{
+		Id:      2,
+		AppName: "New",
+		ExePath: "/games/new.exe",
+	}
+
+	result := Diff([]Shortcut{chess, pikmin, gone}, []Shortcut{renamedPikmin, changedChess, added})
+
+	if len(result.Entries) != 4 {
+		t.Fatal("Unexpected number of entries -", result.Entries)
+	}
+
+	if result.Entries[0].Kind != EntryRemoved || result.Entries[0].Old.AppName != "Gone" {
+		t.Fatal("Unexpected first entry -", result.Entries[0])
+	}
+
+	renamed := result.Entries[1]
+	if renamed.Kind != EntryModified || len(renamed.Fields) != 2 || renamed.Fields[0].Field != "AppName" ||
+		renamed.Fields[1].Field != "FlatpakAppID" || !renamed.Fields[1].IsUnknownField {
+		t.Fatal("Unexpected renamed entry -", renamed)
+	}
+
+	if result.Entries[2].Kind != EntryModified || result.Entries[2].Fields[0].Field != "Tags" {
+		t.Fatal("Unexpected modified entry -", result.Entries[2])
+	}
+
+	if result.Entries[3].Kind != EntryAdded || result.Entries[3].New.AppName != "New" {
+		t.Fatal("Unexpected added entry -", result.Entries[3])
+	}
+
+	expected := `- removed "Gone" (app ID ` + gone.AppId() + `)
+~ modified "Pikmin 2" (app ID 4293732729)
+    AppName: "Pikmin" -> "Pikmin 2"
+    FlatpakAppID: "" -> "org.DolphinEmu.dolphin-emu"
+~ modified "Chess" (app ID ` + chess.AppId() + `)
+    Tags: ["board"] -> ["board", "classic"]
++ added "New" (app ID ` + added.AppId() + `)
+`
+
+	if result.String() != expected {
+		t.Fatal("Unexpected human-readable diff:\n" + result.String())
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !strings.Contains(string(raw), `{"field":"Tags","old":["board"],"new":["board","classic"],"unknown_field":false}`) ||
+		!strings.Contains(string(raw), `"kind":"removed","app_id":"`+gone.AppId()+`","app_name":"Gone"`) ||
+		!strings.Contains(string(raw), `"kind":"modified","app_id":"4293732729","app_name":"Pikmin 2"`) {
+		t.Fatal("Unexpected JSON diff - " + string(raw))
+	}
+
+	if !Diff([]Shortcut{chess}, []Shortcut{chess}).IsEmpty() {
+		t.Fatal("Expected no differences between identical lists")
+	}
+}
+
+func TestUnknownFieldsRoundTrip(t *testing.T) {
+	s := Shortcut{
+		AppName: "Pikmin",
+		ExePath: "/games/pikmin.exe",
+		Tags:    []string{"gamecube"},
+		UnknownFields: []vdf.Field{
+			vdf.NewInt32Field("appid", -1234567),
+			vdf.NewStringField("FlatpakAppID", "org.DolphinEmu.dolphin-emu"),
+		},
+	}
+
+	buffer := bytes.NewBuffer(nil)
+
+	err := WriteVdfV1([]Shortcut{s}, buffer)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	scs, err := ReadVdfV1(buffer)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(scs) != 1 || !scs[0].Equals(s) {
+		t.Fatal("Unknown fields were not preserved -", scs)
+	}
+}
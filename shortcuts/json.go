@@ -0,0 +1,368 @@
+package shortcuts
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+const (
+	// JSONVersion is the version of the JSON document written by
+	// WriteJSON.
+	JSONVersion = 1
+
+	unknownFieldString  = "string"
+	unknownFieldInt32   = "int32"
+	unknownFieldStrings = "strings"
+)
+
+// JSONSchema is the JSON Schema of the document written by WriteJSON.
+// It is also available as 'shortcuts.schema.json' in this package's
+// directory.
+//
+//go:embed shortcuts.schema.json
+var JSONSchema []byte
+
+// jsonDocument is the document written by WriteJSON.
+type jsonDocument struct {
+	Version   int        `json:"version"`
+	Shortcuts []Shortcut `json:"shortcuts"`
+}
+
+// jsonShortcut is the JSON encoding of a Shortcut.
+type jsonShortcut struct {
+	AppId              string             `json:"app_id,omitempty"`
+	GameId             string             `json:"game_id,omitempty"`
+	AppName            string             `json:"app_name"`
+	ExePath            string             `json:"exe_path"`
+	StartDir           string             `json:"start_dir"`
+	IconPath           string             `json:"icon_path"`
+	ShortcutPath       string             `json:"shortcut_path"`
+	LaunchOptions      *string            `json:"launch_options,omitempty"`
+	LaunchArgs         []string           `json:"launch_args,omitempty"`
+	IsHidden           bool               `json:"is_hidden"`
+	AllowDesktopConfig bool               `json:"allow_desktop_config"`
+	AllowOverlay       bool               `json:"allow_overlay"`
+	IsOpenVr           bool               `json:"is_openvr"`
+	LastPlayTimeEpoch  int32              `json:"last_play_time"`
+	Tags               []string           `json:"tags"`
+	UnknownFields      []jsonUnknownField `json:"unknown_fields,omitempty"`
+}
+
+// jsonUnknownField is the JSON encoding of one of a Shortcut's
+// UnknownFields.
+type jsonUnknownField struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MarshalJSON encodes the Shortcut as a JSON object. The object includes
+// the Shortcut's app ID and legacy game ID, as well as its launch options
+// split into arguments. The app ID is the one that newer versions of Steam
+// store in the shortcuts file, if present, and is otherwise generated from
+// the Shortcut's executable path and name. The Id field is not included,
+// as it is the position of the Shortcut in its file. See JSONSchema for
+// the format.
+func (o Shortcut) MarshalJSON() ([]byte, error) {
+	launchOptions := o.LaunchOptions
+
+	encoded := jsonShortcut{
+		AppId:              steamAppId(o),
+		GameId:             o.LegacyId(),
+		AppName:            o.AppName,
+		ExePath:            o.ExePath,
+		StartDir:           o.StartDir,
+		IconPath:           o.IconPath,
+		ShortcutPath:       o.ShortcutPath,
+		LaunchOptions:      &launchOptions,
+		LaunchArgs:         o.LaunchArgs(),
+		IsHidden:           o.IsHidden,
+		AllowDesktopConfig: o.AllowDesktopConfig,
+		AllowOverlay:       o.AllowOverlay,
+		IsOpenVr:           o.IsOpenVr,
+		LastPlayTimeEpoch:  o.LastPlayTimeEpoch,
+		Tags:               o.Tags,
+	}
+
+	if encoded.Tags == nil {
+		encoded.Tags = []string{}
+	}
+
+	for _, f := range o.UnknownFields {
+		unknown := jsonUnknownField{
+			Name: f.Name(),
+		}
+
+		var value interface{}
+
+		switch v := f.UntypedValue().(type) {
+		case string:
+			unknown.Type = unknownFieldString
+			value = v
+		case int32:
+			unknown.Type = unknownFieldInt32
+			value = v
+		case []string:
+			unknown.Type = unknownFieldStrings
+			value = v
+		default:
+			return nil, errors.New("the unknown field '" + f.Name() + "' has an unsupported type")
+		}
+
+		raw, err := marshalJSON(value)
+		if err != nil {
+			return nil, err
+		}
+
+		unknown.Value = raw
+
+		encoded.UnknownFields = append(encoded.UnknownFields, unknown)
+	}
+
+	return marshalJSON(encoded)
+}
+
+// UnmarshalJSON decodes a Shortcut from a JSON object created by
+// MarshalJSON. The app ID and game ID are ignored, as they are derived
+// from the Shortcut's name and executable path. The launch options are
+// taken from 'launch_options' if present. Otherwise, they are created
+// by joining 'launch_args'.
+func (o *Shortcut) UnmarshalJSON(raw []byte) error {
+	var decoded jsonShortcut
+
+	err := json.Unmarshal(raw, &decoded)
+	if err != nil {
+		return err
+	}
+
+	s := Shortcut{
+		Id:                 o.Id,
+		AppName:            decoded.AppName,
+		ExePath:            decoded.ExePath,
+		StartDir:           decoded.StartDir,
+		IconPath:           decoded.IconPath,
+		ShortcutPath:       decoded.ShortcutPath,
+		IsHidden:           decoded.IsHidden,
+		AllowDesktopConfig: decoded.AllowDesktopConfig,
+		AllowOverlay:       decoded.AllowOverlay,
+		IsOpenVr:           decoded.IsOpenVr,
+		LastPlayTimeEpoch:  decoded.LastPlayTimeEpoch,
+		Tags:               decoded.Tags,
+	}
+
+	if decoded.LaunchOptions != nil {
+		s.LaunchOptions = *decoded.LaunchOptions
+	} else {
+		s.LaunchOptions = JoinLaunchArgs(decoded.LaunchArgs)
+	}
+
+	for _, unknown := range decoded.UnknownFields {
+		var f vdf.Field
+
+		switch unknown.Type {
+		case unknownFieldString:
+			var v string
+			err = json.Unmarshal(unknown.Value, &v)
+			f = vdf.NewStringField(unknown.Name, v)
+		case unknownFieldInt32:
+			var v int32
+			err = json.Unmarshal(unknown.Value, &v)
+			f = vdf.NewInt32Field(unknown.Name, v)
+		case unknownFieldStrings:
+			var v []string
+			err = json.Unmarshal(unknown.Value, &v)
+			f = vdf.NewSliceField(unknown.Name, v)
+		default:
+			return errors.New("the unknown field '" + unknown.Name + "' has an unsupported type '" +
+				unknown.Type + "'")
+		}
+		if err != nil {
+			return errors.New("failed to decode the value of unknown field '" + unknown.Name + "' - " +
+				err.Error())
+		}
+
+		s.UnknownFields = append(s.UnknownFields, f)
+	}
+
+	*o = s
+
+	return nil
+}
+
+// LaunchArgs splits the Shortcut's launch options into arguments using
+// the rules of Windows command lines. Arguments are separated by
+// whitespace, and double quotes group an argument that contains
+// whitespace. A double quote preceded by a backslash is a literal
+// double quote. Backslashes are only special when they precede a
+// double quote, so Windows paths do not need to be escaped.
+func (o *Shortcut) LaunchArgs() []string {
+	var args []string
+	var current strings.Builder
+
+	inQuotes := false
+	inArg := false
+
+	runes := []rune(o.LaunchOptions)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\\':
+			slashes := 1
+			for i+slashes < len(runes) && runes[i+slashes] == '\\' {
+				slashes++
+			}
+
+			i += slashes - 1
+			inArg = true
+
+			if i+1 < len(runes) && runes[i+1] == '"' {
+				// 2n backslashes followed by a double quote are n
+				// backslashes and a quote that groups. 2n+1 are n
+				// backslashes and a literal double quote.
+				current.WriteString(strings.Repeat(`\`, slashes/2))
+
+				if slashes%2 == 1 {
+					current.WriteRune('"')
+					i++
+				}
+			} else {
+				current.WriteString(strings.Repeat(`\`, slashes))
+			}
+		case r == '"':
+			inQuotes = !inQuotes
+			inArg = true
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args
+}
+
+// JoinLaunchArgs joins arguments into launch options that LaunchArgs
+// splits into the same arguments. Arguments that are empty or contain
+// whitespace are surrounded by double quotes, and double quotes within
+// an argument are escaped with a backslash.
+func JoinLaunchArgs(args []string) string {
+	quoted := make([]string, len(args))
+
+	for i, arg := range args {
+		quoted[i] = escapeLaunchArg(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// escapeLaunchArg escapes an argument so that LaunchArgs parses it as
+// a single argument.
+func escapeLaunchArg(arg string) string {
+	needsQuotes := len(arg) == 0 || strings.ContainsAny(arg, " \t\n")
+
+	var escaped strings.Builder
+
+	if needsQuotes {
+		escaped.WriteRune('"')
+	}
+
+	slashes := 0
+
+	for _, r := range arg {
+		switch r {
+		case '\\':
+			slashes++
+		case '"':
+			// Escape the preceding backslashes and the quote.
+			escaped.WriteString(strings.Repeat(`\`, slashes+1))
+			slashes = 0
+		default:
+			slashes = 0
+		}
+
+		escaped.WriteRune(r)
+	}
+
+	if needsQuotes {
+		// Escape trailing backslashes so that they do not escape
+		// the closing quote.
+		escaped.WriteString(strings.Repeat(`\`, slashes))
+		escaped.WriteRune('"')
+	}
+
+	return escaped.String()
+}
+
+// marshalJSON encodes v without escaping HTML characters, which are
+// common in launch options (for example, '&&').
+func marshalJSON(v interface{}) ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	err := encoder.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// ReadJSON reads shortcuts from a JSON document written by WriteJSON.
+// Each Shortcut's Id is set to its position in the document.
+func ReadJSON(r io.Reader) ([]Shortcut, error) {
+	var doc jsonDocument
+
+	err := json.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return nil, errors.New("failed to decode shortcuts JSON - " + err.Error())
+	}
+
+	if doc.Version != JSONVersion {
+		return nil, errors.New("unsupported shortcuts JSON version " + strconv.Itoa(doc.Version))
+	}
+
+	for i := range doc.Shortcuts {
+		doc.Shortcuts[i].Id = i
+	}
+
+	return doc.Shortcuts, nil
+}
+
+// WriteJSON writes the provided shortcuts to the specified io.Writer as
+// an indented JSON document. The document is described by JSONSchema.
+func WriteJSON(shortcuts []Shortcut, w io.Writer) error {
+	doc := jsonDocument{
+		Version:   JSONVersion,
+		Shortcuts: shortcuts,
+	}
+
+	if doc.Shortcuts == nil {
+		doc.Shortcuts = []Shortcut{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(doc)
+}
@@ -0,0 +1,148 @@
+package shortcuts
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stephen-fox/steamutil/vdf"
+)
+
+func TestWriteAndReadJSON(t *testing.T) {
+	scs := []Shortcut{
+		{
+			Id:                0,
+			AppName:           "Pikmin",
+			ExePath:           `D:\Program Files\Dolphin\Dolphin.exe`,
+			StartDir:          `D:\Program Files\Dolphin`,
+			LaunchOptions:     `-b -e "D:\Games\Pikmin.iso" && echo done`,
+			AllowOverlay:      true,
+			LastPlayTimeEpoch: 1538448950,
+			Tags:              []string{"gamecube"},
+			UnknownFields: []vdf.Field{
+				vdf.NewInt32Field("appid", -1234567),
+				vdf.NewStringField("FlatpakAppID", "org.DolphinEmu.dolphin-emu"),
+			},
+		},
+		{
+			Id:      1,
+			AppName: "Chess",
+			ExePath: "/Applications/Chess.app",
+		},
+	}
+
+	buffer := bytes.NewBuffer(nil)
+
+	err := WriteJSON(scs, buffer)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, expected := range []string{
+		`"version": 1`,
+		`"app_id": "4293732729"`,
+		`"game_id": "11271507026838028288"`,
+		`"launch_options": "-b -e \"D:\\Games\\Pikmin.iso\" && echo done"`,
+		`"D:\\Games\\Pikmin.iso"`,
+		`"type": "int32"`,
+		`"tags": []`,
+	} {
+		if !strings.Contains(buffer.String(), expected) {
+			t.Fatal("Expected JSON to contain '" + expected + "':\n" + buffer.String())
+		}
+	}
+
+	decoded, err := ReadJSON(buffer)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(decoded) != len(scs) {
+		t.Fatal("Unexpected number of shortcuts -", decoded)
+	}
+
+	for i := range scs {
+		if !decoded[i].Equals(scs[i]) {
+			t.Fatal("Shortcut did not survive the round trip -", decoded[i])
+		}
+	}
+}
+
+func TestShortcut_UnmarshalJSONLaunchArgs(t *testing.T) {
+	var s Shortcut
+
+	err := json.Unmarshal([]byte(`{"app_name":"Pikmin","exe_path":"/usr/bin/dolphin-emu",
+		"launch_args":["-b","-e","/games/Pikmin 2.iso"],"app_id":"1"}`), &s)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if s.LaunchOptions != `-b -e "/games/Pikmin 2.iso"` {
+		t.Fatal("Unexpected launch options - '" + s.LaunchOptions + "'")
+	}
+
+	if !reflect.DeepEqual(s.LaunchArgs(), []string{"-b", "-e", "/games/Pikmin 2.iso"}) {
+		t.Fatal("Unexpected launch arguments -", s.LaunchArgs())
+	}
+}
+
+func TestJoinLaunchArgs(t *testing.T) {
+	tests := map[string][]string{
+		`-b`:                       {"-b"},
+		`"" a\"b`:                  {"", `a"b`},
+		`"D:\Games\Pikmin 2.iso"`:  {`D:\Games\Pikmin 2.iso`},
+		`"C:\Program Files\\"`:     {`C:\Program Files\`},
+		`-e "say \"hello world\""`: {"-e", `say "hello world"`},
+		`\\server\share\\\"x`:      {`\\server\share\"x`},
+	}
+
+	for expected, args := range tests {
+		joined := JoinLaunchArgs(args)
+		if joined != expected {
+			t.Fatal("Unexpected launch options - expected '" + expected + "' - got '" + joined + "'")
+		}
+
+		s := Shortcut{
+			LaunchOptions: joined,
+		}
+
+		if !reflect.DeepEqual(s.LaunchArgs(), args) {
+			t.Fatal("Launch arguments did not survive the round trip -", s.LaunchArgs())
+		}
+	}
+}
+
+func TestReadJSONUnsupportedVersion(t *testing.T) {
+	_, err := ReadJSON(strings.NewReader(`{"version":2,"shortcuts":[]}`))
+	if err == nil {
+		t.Fatal("Expected an error for an unsupported version")
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	var schema struct {
+		Defs struct {
+			Shortcut struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"shortcut"`
+		} `json:"$defs"`
+	}
+
+	err := json.Unmarshal(JSONSchema, &schema)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	fields := reflect.TypeOf(jsonShortcut{})
+
+	for i := 0; i < fields.NumField(); i++ {
+		name := strings.Split(fields.Field(i).Tag.Get("json"), ",")[0]
+
+		_, ok := schema.Defs.Shortcut.Properties[name]
+		if !ok {
+			t.Fatal("The JSON schema is missing the '" + name + "' property")
+		}
+	}
+}
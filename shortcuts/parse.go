@@ -92,6 +92,8 @@ func objectToShortcut(object vdf.Object) Shortcut {
 			s.LastPlayTimeEpoch = f.Int32Value()
 		case tagsField:
 			s.Tags = f.SliceValue()
+		default:
+			s.UnknownFields = append(s.UnknownFields, f)
 		}
 	}
 
@@ -3,6 +3,7 @@ package shortcuts
 import (
 	"strings"
 
+	"github.com/stephen-fox/steamutil/naming"
 	"github.com/stephen-fox/steamutil/vdf"
 )
 
@@ -38,6 +39,12 @@ type Shortcut struct {
 	IsOpenVr           bool
 	LastPlayTimeEpoch  int32
 	Tags               []string
+
+	// UnknownFields are the fields of the shortcut's entry that are
+	// not represented by the other Shortcut fields, such as fields
+	// added by newer versions of Steam. They are preserved when the
+	// Shortcut is written.
+	UnknownFields []vdf.Field
 }
 
 // Equals returns true if the Shortcut is the same as another.
@@ -100,7 +107,18 @@ func (o *Shortcut) Equals(s Shortcut) bool {
 		}
 	}
 
-	return true
+	return len(unknownFieldChanges(*o, s)) == 0
+}
+
+// AppId returns the Shortcut's 32-bit non-Steam app ID. This is the ID
+// that current versions of Steam use for grid image file names.
+func (o *Shortcut) AppId() string {
+	return naming.NonSteamAppId(o.AppName, appendDoubleQuotesIfNeeded(o.ExePath))
+}
+
+// LegacyId returns the Shortcut's legacy 64-bit non-Steam game ID.
+func (o *Shortcut) LegacyId() string {
+	return naming.LegacyNonSteamGameId(o.AppName, appendDoubleQuotesIfNeeded(o.ExePath))
 }
 
 func (o *Shortcut) object() vdf.Object {
@@ -130,6 +148,10 @@ func (o *Shortcut) object() vdf.Object {
 
 	object.Append(vdf.NewInt32Field(lastPlayTimeField, o.LastPlayTimeEpoch))
 
+	for _, f := range o.UnknownFields {
+		object.Append(f)
+	}
+
 	object.Append(vdf.NewSliceField(tagsField, o.Tags))
 
 	return object
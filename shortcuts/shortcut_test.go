@@ -60,3 +60,20 @@ func TestShortcut_EqualsPositive(t *testing.T) {
 		t.Error("Shortcut 1 is not equal even though they are the same")
 	}
 }
+
+func TestShortcut_AppId(t *testing.T) {
+	s := Shortcut{
+		AppName: "Pikmin",
+		ExePath: `D:\Program Files\Dolphin\Dolphin.exe`,
+	}
+
+	appId := s.AppId()
+	if appId != "2624352236" {
+		t.Error("Unexpected app ID - '" + appId + "'")
+	}
+
+	legacyId := s.LegacyId()
+	if legacyId != "11271507026838028288" {
+		t.Error("Unexpected legacy ID - '" + legacyId + "'")
+	}
+}
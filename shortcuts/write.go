@@ -1,10 +1,12 @@
 package shortcuts
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"os"
 
+	"github.com/stephen-fox/steamutil/locations"
 	"github.com/stephen-fox/steamutil/vdf"
 )
 
@@ -28,6 +30,11 @@ type CreateOrUpdateConfig struct {
 	// Path is the path to the shortcuts file.
 	Path string
 
+	// FileSystem is the file system that Path refers to, such as
+	// the FileSystem of a locations.DataVerifier. This defaults to
+	// the operating system's file system if not specified.
+	FileSystem locations.FileSystem
+
 	// Mode is the mode to set the file to if a new file is created.
 	// This defaults to defaultFileMode if not specified.
 	Mode os.FileMode
@@ -42,6 +49,11 @@ type CreateOrUpdateConfig struct {
 	// NoMatch is the function to execute when no shortcut is found
 	// for the provided match criteria.
 	NoMatch func(name string) (s Shortcut, doNothing bool)
+
+	// RefuseIfSteamRunning specifies whether or not the file should
+	// be left unchanged if Steam is running, as described by
+	// locations.CheckSteamNotRunning.
+	RefuseIfSteamRunning bool
 }
 
 // IsValid returns a non-nil error if the configuration is invalid.
@@ -66,6 +78,10 @@ func (o *CreateOrUpdateConfig) IsValid() error {
 		o.Mode = defaultFileMode
 	}
 
+	if o.FileSystem == nil {
+		o.FileSystem = locations.OSFileSystem()
+	}
+
 	return nil
 }
 
@@ -82,23 +98,29 @@ func CreateOrUpdateVdfV1File(config CreateOrUpdateConfig) (UpdateResult, error)
 		return Unchanged, err
 	}
 
+	if config.RefuseIfSteamRunning {
+		err := locations.CheckSteamNotRunning(config.FileSystem)
+		if err != nil {
+			return Unchanged, err
+		}
+	}
+
 	alreadyExists := false
+	mode := config.Mode
+
+	var currentScs []Shortcut
 
-	_, statErr := os.Stat(config.Path)
+	info, statErr := config.FileSystem.Stat(config.Path)
 	if statErr == nil {
 		alreadyExists = true
-	}
-
-	f, err := os.OpenFile(config.Path, os.O_RDWR|os.O_CREATE, config.Mode)
-	if err != nil {
-		return Unchanged, err
-	}
-	defer f.Close()
+		mode = info.Mode().Perm()
 
-	var currentScs []Shortcut
+		raw, err := config.FileSystem.ReadFile(config.Path)
+		if err != nil {
+			return Unchanged, err
+		}
 
-	if alreadyExists {
-		currentScs, err = ReadVdfV1File(f)
+		currentScs, err = ReadVdfV1(bytes.NewReader(raw))
 		if err != nil {
 			return Unchanged, err
 		}
@@ -125,7 +147,14 @@ func CreateOrUpdateVdfV1File(config CreateOrUpdateConfig) (UpdateResult, error)
 		}
 	}
 
-	err = OverwriteVdfV1File(f, currentScs)
+	buffer := bytes.NewBuffer(nil)
+
+	err = WriteVdfV1(currentScs, buffer)
+	if err != nil {
+		return Unchanged, err
+	}
+
+	err = config.FileSystem.WriteFile(config.Path, buffer.Bytes(), mode)
 	if err != nil {
 		return Unchanged, err
 	}
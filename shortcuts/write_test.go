@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path"
 	"testing"
+
+	"github.com/stephen-fox/steamutil/locations"
 )
 
 func TestWriteVdfV1(t *testing.T) {
@@ -156,3 +159,69 @@ func TestReadAndWriteFromFile(t *testing.T) {
 		orignalFileContents + "\nNew:     ", newFileContents)
 	}
 }
+
+func TestCreateOrUpdateVdfV1File_FileSystem(t *testing.T) {
+	dv, err := locations.NewFSDataVerifier(locations.DirFS(t.TempDir()))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	configDirPath := path.Dir(locations.ShortcutsFilePath(dv.RootDirPath(), "12345678"))
+
+	err = locations.FileSystemOf(dv).MkdirAll(configDirPath, 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	config := CreateOrUpdateConfig{
+		Path:       locations.ShortcutsFilePath(dv.RootDirPath(), "12345678"),
+		FileSystem: locations.FileSystemOf(dv),
+		MatchName:  "Chess",
+		OnMatch: func(name string, match *Shortcut) {
+			match.LaunchOptions = "-updated"
+		},
+		NoMatch: func(name string) (Shortcut, bool) {
+			return Shortcut{
+				AppName: name,
+				ExePath: "/Applications/Chess.app",
+			}, false
+		},
+	}
+
+	result, err := CreateOrUpdateVdfV1File(config)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if result != CreatedNewFile {
+		t.Fatal("Unexpected result -", result)
+	}
+
+	result, err = CreateOrUpdateVdfV1File(config)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if result != UpdatedEntry {
+		t.Fatal("Unexpected result -", result)
+	}
+
+	_, _, err = dv.ShortcutsFilePath("12345678")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	raw, err := locations.FileSystemOf(dv).ReadFile(config.Path)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	scs, err := ReadVdfV1(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(scs) != 1 || scs[0].LaunchOptions != "-updated" {
+		t.Fatal("Unexpected shortcuts -", scs)
+	}
+}
@@ -1,3 +1,3 @@
-// Package vdf provides functionality for working with Steam's .vdf
-// file format.
+// Package vdf provides functionality for working with Steam's binary
+// and text .vdf file formats.
 package vdf
@@ -94,7 +94,7 @@ func (o *defaultField) Append(sb *strings.Builder, version FormatVersion) error
 	case V1:
 		o.appendFieldV1(sb)
 	default:
-		return errors.New("Format " + string(version) + " is not supported")
+		return errors.New("Format " + strconv.Itoa(int(version)) + " is not supported")
 	}
 
 	return nil
@@ -261,7 +261,7 @@ func NewRawObjectParser(rawData string, version FormatVersion) (RawObjectParser,
 		}, nil
 	}
 
-	return &v1ObjectParser{}, errors.New("Format version " + string(version) + " is not supported")
+	return &v1ObjectParser{}, errors.New("Format version " + strconv.Itoa(int(version)) + " is not supported")
 }
 
 func parseRawInt32Value(raw string) int32 {
@@ -0,0 +1,366 @@
+package vdf
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// KeyValue is a node in a text (KeyValues) .vdf document, such as
+// libraryfolders.vdf, loginusers.vdf or an appmanifest .acf file.
+//
+// A KeyValue is either a string value, in which case Value is set,
+// or an object, in which case Children holds its members. Keys are
+// matched case-insensitively, as Steam does.
+type KeyValue struct {
+	Key      string
+	Value    string
+	Children []*KeyValue
+	IsObject bool
+}
+
+// NewTextObject creates a KeyValue object with the specified key.
+func NewTextObject(key string) *KeyValue {
+	return &KeyValue{
+		Key:      key,
+		IsObject: true,
+	}
+}
+
+// Get returns the first child with the specified key.
+func (o *KeyValue) Get(key string) (*KeyValue, bool) {
+	for _, child := range o.Children {
+		if strings.EqualFold(child.Key, key) {
+			return child, true
+		}
+	}
+
+	return nil, false
+}
+
+// Lookup returns the descendant found by following the specified keys.
+func (o *KeyValue) Lookup(keys ...string) (*KeyValue, bool) {
+	current := o
+
+	for _, key := range keys {
+		var ok bool
+
+		current, ok = current.Get(key)
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// StringValue returns the string value of the descendant found by
+// following the specified keys. It returns false if the descendant
+// does not exist, or if it is an object.
+func (o *KeyValue) StringValue(keys ...string) (string, bool) {
+	kv, ok := o.Lookup(keys...)
+	if !ok || kv.IsObject {
+		return "", false
+	}
+
+	return kv.Value, true
+}
+
+// Set sets the string value of the child with the specified key,
+// adding the child if it does not exist.
+func (o *KeyValue) Set(key string, value string) *KeyValue {
+	child, ok := o.Get(key)
+	if !ok {
+		child = &KeyValue{
+			Key: key,
+		}
+
+		o.Children = append(o.Children, child)
+	}
+
+	child.Value = value
+	child.Children = nil
+	child.IsObject = false
+
+	return child
+}
+
+// SetObject returns the object child with the specified key, adding
+// it if it does not exist. A string child with the same key is
+// replaced by an empty object.
+func (o *KeyValue) SetObject(key string) *KeyValue {
+	child, ok := o.Get(key)
+	if !ok {
+		child = NewTextObject(key)
+		o.Children = append(o.Children, child)
+	}
+
+	if !child.IsObject {
+		child.Value = ""
+		child.IsObject = true
+	}
+
+	return child
+}
+
+// Remove removes every child with the specified key. It returns true
+// if a child was removed.
+func (o *KeyValue) Remove(key string) bool {
+	removed := false
+	kept := o.Children[:0]
+
+	for _, child := range o.Children {
+		if strings.EqualFold(child.Key, key) {
+			removed = true
+			continue
+		}
+
+		kept = append(kept, child)
+	}
+
+	o.Children = kept
+
+	return removed
+}
+
+// ParseText parses a text .vdf document. The result is an unnamed
+// object whose children are the document's top-level keys.
+func ParseText(r io.Reader) (*KeyValue, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &textParser{
+		raw: string(raw),
+	}
+
+	root := NewTextObject("")
+
+	err = p.parseChildren(root, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// WriteText writes the children of the specified object as a text
+// .vdf document, using the same layout as Steam.
+func WriteText(w io.Writer, root *KeyValue) error {
+	bw := bufio.NewWriter(w)
+
+	for _, child := range root.Children {
+		writeTextKeyValue(bw, child, 0)
+	}
+
+	return bw.Flush()
+}
+
+func writeTextKeyValue(w *bufio.Writer, kv *KeyValue, depth int) {
+	indent := strings.Repeat("\t", depth)
+
+	w.WriteString(indent)
+	w.WriteString(quoteText(kv.Key))
+
+	if !kv.IsObject {
+		w.WriteString("\t\t")
+		w.WriteString(quoteText(kv.Value))
+		w.WriteString("\n")
+		return
+	}
+
+	w.WriteString("\n")
+	w.WriteString(indent)
+	w.WriteString("{\n")
+
+	for _, child := range kv.Children {
+		writeTextKeyValue(w, child, depth+1)
+	}
+
+	w.WriteString(indent)
+	w.WriteString("}\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+
+func quoteText(s string) string {
+	return `"` + textEscaper.Replace(s) + `"`
+}
+
+type textParser struct {
+	raw string
+	pos int
+}
+
+// parseChildren parses key value pairs into the parent object until
+// a closing brace (if isNested is true) or the end of the document.
+func (o *textParser) parseChildren(parent *KeyValue, isNested bool) error {
+	for {
+		token, isQuoted, err := o.next()
+		if err == io.EOF {
+			if isNested {
+				return errors.New("unexpected end of document - missing closing brace for '" + parent.Key + "'")
+			}
+
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !isQuoted {
+			switch token {
+			case "}":
+				if !isNested {
+					return errors.New("unexpected closing brace at offset " + strconv.Itoa(o.pos))
+				}
+
+				return nil
+			case "{":
+				return errors.New("unexpected opening brace at offset " + strconv.Itoa(o.pos))
+			}
+		}
+
+		key := token
+
+		token, isQuoted, err = o.next()
+		if err == io.EOF {
+			return errors.New("unexpected end of document - missing value for '" + key + "'")
+		}
+		if err != nil {
+			return err
+		}
+
+		if !isQuoted && token == "}" {
+			return errors.New("missing value for '" + key + "' at offset " + strconv.Itoa(o.pos))
+		}
+
+		if !isQuoted && token == "{" {
+			child := NewTextObject(key)
+
+			err := o.parseChildren(child, true)
+			if err != nil {
+				return err
+			}
+
+			parent.Children = append(parent.Children, child)
+		} else {
+			parent.Children = append(parent.Children, &KeyValue{
+				Key:   key,
+				Value: token,
+			})
+		}
+
+		o.skipConditional()
+	}
+}
+
+// next returns the next token in the document, skipping whitespace
+// and comments. It returns io.EOF if there are no more tokens.
+func (o *textParser) next() (string, bool, error) {
+	o.skipSpaceAndComments()
+
+	if o.pos >= len(o.raw) {
+		return "", false, io.EOF
+	}
+
+	switch o.raw[o.pos] {
+	case '{', '}':
+		o.pos++
+		return o.raw[o.pos-1 : o.pos], false, nil
+	case '"':
+		value, err := o.quoted()
+		return value, true, err
+	}
+
+	start := o.pos
+
+	for o.pos < len(o.raw) && !isTextDelim(o.raw[o.pos]) {
+		o.pos++
+	}
+
+	return o.raw[start:o.pos], false, nil
+}
+
+func (o *textParser) quoted() (string, error) {
+	start := o.pos
+	o.pos++
+
+	sb := &strings.Builder{}
+
+	for o.pos < len(o.raw) {
+		c := o.raw[o.pos]
+		o.pos++
+
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if o.pos >= len(o.raw) {
+				break
+			}
+
+			escaped := o.raw[o.pos]
+			o.pos++
+
+			switch escaped {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case '\\', '"':
+				sb.WriteByte(escaped)
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(escaped)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", errors.New("unterminated string starting at offset " + strconv.Itoa(start))
+}
+
+func (o *textParser) skipSpaceAndComments() {
+	for o.pos < len(o.raw) {
+		switch {
+		case isTextSpace(o.raw[o.pos]):
+			o.pos++
+		case strings.HasPrefix(o.raw[o.pos:], "//"):
+			end := strings.IndexByte(o.raw[o.pos:], '\n')
+			if end < 0 {
+				o.pos = len(o.raw)
+			} else {
+				o.pos += end + 1
+			}
+		default:
+			return
+		}
+	}
+}
+
+// skipConditional skips a platform conditional, such as '[$WIN32]',
+// that follows a value. Conditionals are not evaluated.
+func (o *textParser) skipConditional() {
+	o.skipSpaceAndComments()
+
+	if o.pos < len(o.raw) && o.raw[o.pos] == '[' {
+		end := strings.IndexByte(o.raw[o.pos:], ']')
+		if end >= 0 {
+			o.pos += end + 1
+		}
+	}
+}
+
+func isTextSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func isTextDelim(c byte) bool {
+	return isTextSpace(c) || c == '"' || c == '{' || c == '}'
+}
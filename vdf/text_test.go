@@ -0,0 +1,91 @@
+package vdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseText(t *testing.T) {
+	raw := `// A comment
+"Root"
+{
+	"Name"		"Quoted \"value\""
+	Unquoted	value
+	"Path"		"C:\\Games\\Steam"
+	"Windows"		"1"		[$WIN32]
+	"Nested"
+	{
+		"Empty"
+		{
+		}
+	}
+}
+`
+
+	doc, err := ParseText(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := map[string]string{
+		"name":     `Quoted "value"`,
+		"unquoted": "value",
+		"path":     `C:\Games\Steam`,
+		"windows":  "1",
+	}
+
+	for key, value := range expected {
+		result, ok := doc.StringValue("root", key)
+		if !ok {
+			t.Fatal("Missing key '" + key + "'")
+		}
+
+		if result != value {
+			t.Fatal("Unexpected value for '" + key + "' - got '" + result + "'")
+		}
+	}
+
+	empty, ok := doc.Lookup("Root", "Nested", "Empty")
+	if !ok || !empty.IsObject || len(empty.Children) != 0 {
+		t.Fatal("Empty object was not parsed correctly")
+	}
+
+	_, err = ParseText(strings.NewReader(`"Root" { "Key" "Value"`))
+	if err == nil {
+		t.Fatal("Expected an error for a missing closing brace")
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	root := NewTextObject("")
+
+	apps := root.SetObject("Root").SetObject("apps")
+	apps.Set("400", "1")
+	apps.Set("220", `C:\"x"`)
+	apps.Set("400", "2")
+	apps.Set("10", "3")
+	apps.Remove("10")
+
+	buffer := bytes.NewBuffer(nil)
+
+	err := WriteText(buffer, root)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := "\"Root\"\n{\n\t\"apps\"\n\t{\n\t\t\"400\"\t\t\"2\"\n\t\t\"220\"\t\t\"C:\\\\\\\"x\\\"\"\n\t}\n}\n"
+	if buffer.String() != expected {
+		t.Fatal("Unexpected output:\n" + buffer.String())
+	}
+
+	doc, err := ParseText(buffer)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	value, _ := doc.StringValue("Root", "apps", "220")
+	if value != `C:\"x"` {
+		t.Fatal("Value did not survive a round trip - '" + value + "'")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"strconv"
 	"strings"
 )
 
@@ -53,7 +54,7 @@ func (o *defaultVdf) String() (string, error) {
 	case V1:
 		break
 	default:
-		return "", errors.New("The specified format is not supported - " + string(o.config.Version))
+		return "", errors.New("The specified format is not supported - " + strconv.Itoa(int(o.config.Version)))
 	}
 
 	sb.Write(o.config.header)
@@ -167,7 +168,7 @@ func NewConstructor(config Config) (Constructor, error) {
 		}, nil
 	}
 
-	return &v1Constructor{}, errors.New("The specified format is not supported - '" + string(config.Version) + "'")
+	return &v1Constructor{}, errors.New("The specified format is not supported - '" + strconv.Itoa(int(config.Version)) + "'")
 }
 
 func fileHeaderV1(name string) []byte {
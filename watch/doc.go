@@ -0,0 +1,3 @@
+// Package watch provides functionality for observing changes to Steam
+// users' shortcuts and grid artwork.
+package watch
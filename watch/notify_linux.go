@@ -0,0 +1,78 @@
+package watch
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	inotifyMask = unix.IN_CREATE | unix.IN_CLOSE_WRITE | unix.IN_DELETE |
+		unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_ATTRIB
+)
+
+// inotifyNotifier is a notifier that uses inotify. The events themselves
+// are discarded, as the watcher compares the files to their last known
+// state to find the actual changes.
+type inotifyNotifier struct {
+	fd int
+	f  *os.File
+	c  chan struct{}
+}
+
+func newOSNotifier() (notifier, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+
+	// The file descriptor is non-blocking, so reads use the runtime's
+	// poller and are interrupted when the file is closed.
+	n := &inotifyNotifier{
+		fd: fd,
+		f:  os.NewFile(uintptr(fd), "inotify"),
+		c:  make(chan struct{}, 1),
+	}
+
+	go n.loop()
+
+	return n, nil
+}
+
+func (o *inotifyNotifier) loop() {
+	buffer := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+
+	for {
+		_, err := o.f.Read(buffer)
+		if err != nil {
+			return
+		}
+
+		select {
+		case o.c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// watch adds a watch for each directory. Adding a watch for a directory
+// that is already watched has no effect, so directories that were deleted
+// and created again are watched again.
+func (o *inotifyNotifier) watch(dirPaths []string) error {
+	for _, dirPath := range dirPaths {
+		_, err := unix.InotifyAddWatch(o.fd, dirPath, inotifyMask|unix.IN_ONLYDIR)
+		if err != nil && err != unix.ENOENT {
+			return os.NewSyscallError("inotify_add_watch", err)
+		}
+	}
+
+	return nil
+}
+
+func (o *inotifyNotifier) changes() <-chan struct{} {
+	return o.c
+}
+
+func (o *inotifyNotifier) close() error {
+	return o.f.Close()
+}
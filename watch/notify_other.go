@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package watch
+
+import (
+	"errors"
+)
+
+func newOSNotifier() (notifier, error) {
+	return nil, errors.New("file change notifications are not supported on this operating system")
+}
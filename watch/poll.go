@@ -0,0 +1,54 @@
+package watch
+
+import (
+	"time"
+)
+
+// pollNotifier is a notifier that signals a change every interval. The
+// watcher compares the files to their last known state to find the
+// actual changes.
+type pollNotifier struct {
+	ticker *time.Ticker
+	c      chan struct{}
+	done   chan struct{}
+}
+
+func newPollNotifier(interval time.Duration) notifier {
+	n := &pollNotifier{
+		ticker: time.NewTicker(interval),
+		c:      make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	go n.loop()
+
+	return n
+}
+
+func (o *pollNotifier) loop() {
+	for {
+		select {
+		case <-o.done:
+			return
+		case <-o.ticker.C:
+			select {
+			case o.c <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (o *pollNotifier) watch(dirPaths []string) error {
+	return nil
+}
+
+func (o *pollNotifier) changes() <-chan struct{} {
+	return o.c
+}
+
+func (o *pollNotifier) close() error {
+	o.ticker.Stop()
+	close(o.done)
+	return nil
+}
@@ -0,0 +1,514 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/stephen-fox/steamutil/grid"
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/naming"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+const (
+	// ShortcutAdded means that a shortcut was added to a user's
+	// shortcuts file.
+	ShortcutAdded EventKind = iota
+
+	// ShortcutRemoved means that a shortcut was removed from a user's
+	// shortcuts file.
+	ShortcutRemoved
+
+	// ShortcutChanged means that one or more fields of a shortcut
+	// were changed.
+	ShortcutChanged
+
+	// ArtworkAdded means that a file was added to a user's grid
+	// directory.
+	ArtworkAdded
+
+	// ArtworkRemoved means that a file was removed from a user's grid
+	// directory.
+	ArtworkRemoved
+
+	// ArtworkChanged means that a file in a user's grid directory
+	// was replaced.
+	ArtworkChanged
+
+	// WatchError means that a user's files could not be read. The
+	// user's previous state is kept until its files can be read. If
+	// the files could not be read when the watch started, the first
+	// state that can be read becomes the user's initial state, and
+	// no other events are sent for it.
+	WatchError
+)
+
+const (
+	defaultDebounce     = 500 * time.Millisecond
+	defaultPollInterval = 2 * time.Second
+)
+
+// EventKind describes what changed.
+type EventKind int
+
+// String returns a human-readable name for the EventKind.
+func (o EventKind) String() string {
+	switch o {
+	case ShortcutAdded:
+		return "shortcut added"
+	case ShortcutRemoved:
+		return "shortcut removed"
+	case ShortcutChanged:
+		return "shortcut changed"
+	case ArtworkAdded:
+		return "artwork added"
+	case ArtworkRemoved:
+		return "artwork removed"
+	case ArtworkChanged:
+		return "artwork changed"
+	case WatchError:
+		return "error"
+	}
+
+	return "unknown"
+}
+
+// Event is a change to a user's shortcuts or grid artwork.
+type Event struct {
+	// Kind describes what changed.
+	Kind EventKind
+
+	// UserId is the account ID of the user whose files changed.
+	UserId string
+
+	// Shortcut is the shortcut that was added or removed, or the new
+	// version of a changed shortcut.
+	Shortcut shortcuts.Shortcut
+
+	// PreviousShortcut is the old version of a changed shortcut.
+	PreviousShortcut shortcuts.Shortcut
+
+	// Changes are the fields that differ between PreviousShortcut
+	// and Shortcut if the shortcut was changed.
+	Changes []shortcuts.FieldChange
+
+	// Artwork is the grid directory file that was added, removed or
+	// changed. Its Shortcut field is set if it belongs to one of the
+	// user's current shortcuts.
+	Artwork grid.Entry
+
+	// Err is the error that occurred if Kind is WatchError.
+	Err error
+}
+
+// Config configures a watch operation.
+type Config struct {
+	// DataVerifier is used to get the paths of the watched files.
+	DataVerifier locations.DataVerifier
+
+	// UserIds are the Steam user IDs whose files are watched. This
+	// defaults to every user that has a data directory when the watch
+	// starts if not specified.
+	UserIds []string
+
+	// Debounce is how long to wait for file change notifications to
+	// stop before the files are read again. This avoids reading a file
+	// that Steam is still writing. It is not used when polling. This
+	// defaults to defaultDebounce if not specified.
+	Debounce time.Duration
+
+	// PollInterval is how often the files are checked for changes when
+	// the operating system's file change notifications are unavailable.
+	// This defaults to defaultPollInterval if not specified.
+	PollInterval time.Duration
+
+	// ForcePolling specifies whether or not the files should be checked
+	// for changes every PollInterval even if file change notifications
+	// are available.
+	ForcePolling bool
+}
+
+// Validate returns a non-nil error if the Config is invalid.
+func (o *Config) Validate() error {
+	if o.DataVerifier == nil {
+		return errors.New("the DataVerifier cannot be nil")
+	}
+
+	var userIds []string
+
+	if len(o.UserIds) == 0 {
+		idsToDirPaths, err := o.DataVerifier.UserIdsToDataDirPaths()
+		if err != nil {
+			return err
+		}
+
+		for userId := range idsToDirPaths {
+			userIds = append(userIds, userId)
+		}
+	} else {
+		// Copy the IDs so that the caller's slice is not modified.
+		userIds = append(userIds, o.UserIds...)
+	}
+
+	for i := range userIds {
+		accountId, err := naming.ParseUserId(userIds[i])
+		if err != nil {
+			return err
+		}
+
+		userIds[i] = accountId
+	}
+
+	o.UserIds = userIds
+
+	if o.Debounce <= 0 {
+		o.Debounce = defaultDebounce
+	}
+
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultPollInterval
+	}
+
+	return nil
+}
+
+// notifier signals that the watched directories may have changed.
+type notifier interface {
+	// watch starts watching the specified directories if they are not
+	// already watched. Directories that do not exist are ignored.
+	watch(dirPaths []string) error
+
+	// changes returns a channel that receives a value when any of the
+	// watched directories changes.
+	changes() <-chan struct{}
+
+	// close stops the notifier.
+	close() error
+}
+
+// Watch watches the shortcuts files and grid directories of the configured
+// users. Events are sent on the returned channel when the files change. The
+// channel is closed when ctx is done.
+//
+// File change notifications are used when they are supported by the
+// operating system and the DataVerifier uses the operating system's file
+// system. Otherwise, or if a directory cannot be watched (for example,
+// because the inotify watch limit was reached), the files are checked
+// every Config.PollInterval.
+func Watch(ctx context.Context, config Config) (<-chan Event, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &watcher{
+		config:        config,
+		debounce:      config.Debounce,
+		states:        make(map[string]*userState),
+		initialErrors: make(map[string]error),
+		events:        make(chan Event),
+	}
+
+	if !config.ForcePolling && locations.IsOSFileSystem(locations.FileSystemOf(config.DataVerifier)) {
+		w.notifier, err = newOSNotifier()
+		if err != nil {
+			w.notifier = nil
+		}
+	}
+
+	if w.notifier == nil {
+		w.startPolling()
+	} else {
+		w.watchDirs()
+	}
+
+	for _, userId := range config.UserIds {
+		state, err := w.scan(userId, nil)
+		if err != nil {
+			w.initialErrors[userId] = err
+			continue
+		}
+
+		w.states[userId] = state
+	}
+
+	go w.loop(ctx)
+
+	return w.events, nil
+}
+
+type watcher struct {
+	config        Config
+	notifier      notifier
+	debounce      time.Duration
+	states        map[string]*userState
+	initialErrors map[string]error
+	events        chan Event
+}
+
+// startPolling replaces the watcher's notifier, if any, with a
+// pollNotifier. Files are not debounced when polling, as a debounce
+// longer than the poll interval would never expire.
+func (o *watcher) startPolling() {
+	if o.notifier != nil {
+		o.notifier.close()
+	}
+
+	o.notifier = newPollNotifier(o.config.PollInterval)
+	o.debounce = 0
+}
+
+// watchDirs watches the directories that contain the watched files. The
+// watcher falls back to polling if any of the directories cannot be
+// watched, as changes in that directory would otherwise be missed.
+func (o *watcher) watchDirs() {
+	err := o.notifier.watch(o.dirPaths())
+	if err != nil {
+		o.startPolling()
+	}
+}
+
+// userState is the last known state of a user's files.
+type userState struct {
+	shortcutsInfo os.FileInfo
+	shortcuts     []shortcuts.Shortcut
+	artwork       map[string]os.FileInfo
+}
+
+func (o *watcher) loop(ctx context.Context) {
+	defer close(o.events)
+	defer func() {
+		o.notifier.close()
+	}()
+
+	timer := time.NewTimer(o.debounce)
+	timer.Stop()
+
+	for _, userId := range o.config.UserIds {
+		err, failed := o.initialErrors[userId]
+		if !failed {
+			continue
+		}
+
+		if !o.send(ctx, Event{
+			Kind:   WatchError,
+			UserId: userId,
+			Err:    err,
+		}) {
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-o.notifier.changes():
+			timer.Reset(o.debounce)
+		case <-timer.C:
+			o.watchDirs()
+
+			for _, userId := range o.config.UserIds {
+				if !o.update(ctx, userId) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// update reads the user's files and sends events for any differences
+// from the user's last known state. If the user has no known state,
+// the files become the user's initial state instead. It returns false
+// if ctx is done.
+func (o *watcher) update(ctx context.Context, userId string) bool {
+	previous := o.states[userId]
+
+	current, err := o.scan(userId, previous)
+	if err != nil {
+		return o.send(ctx, Event{
+			Kind:   WatchError,
+			UserId: userId,
+			Err:    err,
+		})
+	}
+
+	o.states[userId] = current
+
+	if previous == nil {
+		return true
+	}
+
+	gridDirPath := locations.GridDirPath(o.config.DataVerifier.RootDirPath(), userId)
+
+	for _, event := range diffStates(userId, gridDirPath, previous, current) {
+		if !o.send(ctx, event) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (o *watcher) send(ctx context.Context, event Event) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case o.events <- event:
+		return true
+	}
+}
+
+// scan reads the current state of the user's files. The shortcuts file
+// is only parsed if its size or modification time differs from previous.
+func (o *watcher) scan(userId string, previous *userState) (*userState, error) {
+	fs := locations.FileSystemOf(o.config.DataVerifier)
+	dataDirPath := o.config.DataVerifier.RootDirPath()
+
+	state := &userState{
+		artwork: make(map[string]os.FileInfo),
+	}
+
+	shortcutsFilePath := locations.ShortcutsFilePath(dataDirPath, userId)
+
+	info, err := fs.Stat(shortcutsFilePath)
+	if err == nil {
+		state.shortcutsInfo = info
+
+		if previous != nil && previous.shortcutsInfo != nil &&
+			previous.shortcutsInfo.Size() == info.Size() &&
+			previous.shortcutsInfo.ModTime().Equal(info.ModTime()) {
+			state.shortcuts = previous.shortcuts
+		} else {
+			raw, err := fs.ReadFile(shortcutsFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			state.shortcuts, err = shortcuts.Read(bytes.NewReader(raw))
+			if err != nil {
+				return nil, errors.New("failed to parse shortcuts file '" +
+					shortcutsFilePath + "' - " + err.Error())
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	infos, err := fs.ReadDir(locations.GridDirPath(dataDirPath, userId))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for _, info := range infos {
+		if info.Mode().IsRegular() {
+			state.artwork[info.Name()] = info
+		}
+	}
+
+	return state, nil
+}
+
+// dirPaths returns the directories that contain the watched files.
+func (o *watcher) dirPaths() []string {
+	dataDirPath := o.config.DataVerifier.RootDirPath()
+
+	var dirPaths []string
+
+	for _, userId := range o.config.UserIds {
+		dirPaths = append(dirPaths,
+			locations.UserIdDirPath(dataDirPath, userId),
+			path.Dir(locations.ShortcutsFilePath(dataDirPath, userId)),
+			locations.GridDirPath(dataDirPath, userId))
+	}
+
+	return dirPaths
+}
+
+// diffStates returns the events that describe the differences between
+// two states of a user's files. Shortcuts are paired as described by
+// shortcuts.Diff.
+func diffStates(userId string, gridDirPath string, previous *userState, current *userState) []Event {
+	var events []Event
+
+	for _, entry := range shortcuts.Diff(previous.shortcuts, current.shortcuts).Entries {
+		switch entry.Kind {
+		case shortcuts.EntryRemoved:
+			events = append(events, Event{
+				Kind:     ShortcutRemoved,
+				UserId:   userId,
+				Shortcut: entry.Old,
+			})
+		case shortcuts.EntryAdded:
+			events = append(events, Event{
+				Kind:     ShortcutAdded,
+				UserId:   userId,
+				Shortcut: entry.New,
+			})
+		case shortcuts.EntryModified:
+			events = append(events, Event{
+				Kind:             ShortcutChanged,
+				UserId:           userId,
+				Shortcut:         entry.New,
+				PreviousShortcut: entry.Old,
+				Changes:          entry.Fields,
+			})
+		}
+	}
+
+	artworkEvent := func(kind EventKind, name string) Event {
+		entry, _ := grid.ParseFileName(name)
+		entry.Name = name
+		entry.Path = path.Join(gridDirPath, name)
+
+		for i := range current.shortcuts {
+			if entry.Id == current.shortcuts[i].AppId() || entry.Id == current.shortcuts[i].LegacyId() {
+				entry.Shortcut = &current.shortcuts[i]
+				break
+			}
+		}
+
+		return Event{
+			Kind:    kind,
+			UserId:  userId,
+			Artwork: entry,
+		}
+	}
+
+	for _, name := range sortedNames(previous.artwork) {
+		_, ok := current.artwork[name]
+		if !ok {
+			events = append(events, artworkEvent(ArtworkRemoved, name))
+		}
+	}
+
+	for _, name := range sortedNames(current.artwork) {
+		info := current.artwork[name]
+
+		old, ok := previous.artwork[name]
+		if !ok {
+			events = append(events, artworkEvent(ArtworkAdded, name))
+		} else if old.Size() != info.Size() || !old.ModTime().Equal(info.ModTime()) {
+			events = append(events, artworkEvent(ArtworkChanged, name))
+		}
+	}
+
+	return events
+}
+
+func sortedNames(infos map[string]os.FileInfo) []string {
+	names := make([]string, 0, len(infos))
+	for name := range infos {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
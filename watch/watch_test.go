@@ -0,0 +1,230 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stephen-fox/steamutil/locations"
+	"github.com/stephen-fox/steamutil/shortcuts"
+)
+
+const (
+	testUserId = "12345678"
+)
+
+func TestWatch(t *testing.T) {
+	t.Run("notifications", func(t *testing.T) {
+		testWatch(t, false)
+	})
+
+	t.Run("polling", func(t *testing.T) {
+		testWatch(t, true)
+	})
+}
+
+func testWatch(t *testing.T, forcePolling bool) {
+	dataDirPath := t.TempDir()
+
+	dv, err := locations.NewDataVerifierForDir(dataDirPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	chess := shortcuts.Shortcut{
+		AppName: "Chess",
+		ExePath: "/Applications/Chess.app",
+	}
+
+	pikmin := shortcuts.Shortcut{
+		Id:      1,
+		AppName: "Pikmin",
+		ExePath: "/games/pikmin.exe",
+	}
+
+	writeTestShortcuts(t, dataDirPath, []shortcuts.Shortcut{chess, pikmin})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := Watch(ctx, Config{
+		DataVerifier: dv,
+		Debounce:     50 * time.Millisecond,
+		PollInterval: 50 * time.Millisecond,
+		ForcePolling: forcePolling,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	changedChess := chess
+	changedChess.LaunchOptions = "-fullscreen"
+
+	writeTestShortcuts(t, dataDirPath, []shortcuts.Shortcut{changedChess})
+
+	event := nextEvent(t, events)
+	if event.Kind != ShortcutRemoved || event.UserId != testUserId || event.Shortcut.AppName != "Pikmin" {
+		t.Fatal("Unexpected event -", event)
+	}
+
+	event = nextEvent(t, events)
+	if event.Kind != ShortcutChanged || len(event.Changes) != 1 ||
+		event.Changes[0].Field != "LaunchOptions" || event.Changes[0].New != "-fullscreen" {
+		t.Fatal("Unexpected event -", event)
+	}
+
+	gridDirPath := locations.GridDirPath(dataDirPath, testUserId)
+
+	err = os.MkdirAll(gridDirPath, 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Give the notifier a chance to watch the new directory.
+	time.Sleep(200 * time.Millisecond)
+
+	err = ioutil.WriteFile(path.Join(gridDirPath, chess.AppId()+"p.png"), []byte("image"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	event = nextEvent(t, events)
+	if event.Kind != ArtworkAdded || event.Artwork.Shortcut == nil ||
+		event.Artwork.Shortcut.AppName != "Chess" || event.Artwork.Path != path.Join(gridDirPath, chess.AppId()+"p.png") {
+		t.Fatal("Unexpected event -", event)
+	}
+
+	cancel()
+
+	for range events {
+	}
+}
+
+func TestWatchInitialError(t *testing.T) {
+	dataDirPath := t.TempDir()
+
+	dv, err := locations.NewDataVerifierForDir(dataDirPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	filePath := locations.ShortcutsFilePath(dataDirPath, testUserId)
+
+	err = os.MkdirAll(path.Dir(filePath), 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = ioutil.WriteFile(filePath, []byte("not a shortcuts file"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	userIds := []string{"[U:1:" + testUserId + "]"}
+
+	events, err := Watch(ctx, Config{
+		DataVerifier: dv,
+		UserIds:      userIds,
+		PollInterval: 50 * time.Millisecond,
+		ForcePolling: true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if userIds[0] != "[U:1:"+testUserId+"]" {
+		t.Fatal("The caller's user IDs were modified - '" + userIds[0] + "'")
+	}
+
+	event := nextEvent(t, events)
+	if event.Kind != WatchError || event.UserId != testUserId || event.Err == nil {
+		t.Fatal("Unexpected event -", event)
+	}
+
+	chess := shortcuts.Shortcut{
+		AppName: "Chess",
+		ExePath: "/Applications/Chess.app",
+	}
+
+	writeTestShortcuts(t, dataDirPath, []shortcuts.Shortcut{chess})
+
+	// Wait for the file to become the initial state.
+	deadline := time.After(time.Second)
+
+	for waiting := true; waiting; {
+		select {
+		case event := <-events:
+			if event.Kind != WatchError {
+				t.Fatal("Unexpected event for the initial state -", event)
+			}
+		case <-deadline:
+			waiting = false
+		}
+	}
+
+	chess.LaunchOptions = "-fullscreen"
+
+	writeTestShortcuts(t, dataDirPath, []shortcuts.Shortcut{chess})
+
+	event = nextEvent(t, events)
+	if event.Kind != ShortcutChanged || event.Shortcut.LaunchOptions != "-fullscreen" {
+		t.Fatal("Unexpected event -", event)
+	}
+
+	cancel()
+
+	for range events {
+	}
+}
+
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("Events channel was closed")
+		}
+
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+
+	return Event{}
+}
+
+func writeTestShortcuts(t *testing.T, dataDirPath string, scs []shortcuts.Shortcut) {
+	filePath := locations.ShortcutsFilePath(dataDirPath, testUserId)
+
+	err := os.MkdirAll(path.Dir(filePath), 0700)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	buffer := bytes.NewBuffer(nil)
+
+	err = shortcuts.WriteVdfV1(scs, buffer)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// The file is replaced atomically so that the watcher never reads
+	// a partially written file.
+	tempFilePath := filePath + ".tmp"
+
+	err = ioutil.WriteFile(tempFilePath, buffer.Bytes(), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = os.Rename(tempFilePath, filePath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}